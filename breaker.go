@@ -0,0 +1,380 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors returned while a circuit breaker is open.
+var ErrCircuitOpen = errors.New("sensibo: circuit breaker open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+// Circuit breaker states.
+const (
+	// BreakerClosed lets all requests through and counts failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails all requests right away.
+	BreakerOpen
+	// BreakerHalfOpen lets a few probe requests through to decide
+	// whether to close or open again.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// BreakerConfig configures a CircuitBreaker, zero values use the defaults.
+type BreakerConfig struct {
+	// FailureRatio is the ratio of failed requests (0 to 1) that opens
+	// the breaker, 0.5 by default.
+	FailureRatio float64
+	// MinRequests is the number of requests in the window needed before
+	// the breaker can open, 5 by default.
+	MinRequests int
+	// Window is the period after which the counts of a closed breaker
+	// are reset, 1 minute by default.
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before letting
+	// probe requests through, 30 seconds by default.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of successful probe requests
+	// needed to close the breaker, 1 by default. Only this many
+	// requests are let through while half-open.
+	HalfOpenRequests int
+	// PerEndpoint keeps a separate breaker for every operation
+	// (e.g: "GetDeviceACStates") instead of a single global one.
+	PerEndpoint bool
+	// IsFailure reports whether an error counts as a failure. By default
+	// transport errors and 5xx responses are failures, cancelled
+	// contexts and other API errors are not.
+	IsFailure func(err error) bool
+	// OnStateChange is called after every state transition.
+	OnStateChange func(BreakerStateChange)
+}
+
+// BreakerStateChange describes a transition of a circuit breaker.
+type BreakerStateChange struct {
+	// Key is the operation name with PerEndpoint, empty otherwise.
+	Key  string
+	From BreakerState
+	To   BreakerState
+}
+
+// BreakerOpenError is returned when a request is rejected by an open breaker.
+type BreakerOpenError struct {
+	// Key is the operation name with PerEndpoint, empty otherwise.
+	Key   string
+	State BreakerState
+	// RetryAfter is how long until the breaker lets probe requests
+	// through, zero when half-open.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *BreakerOpenError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%v (%s)", ErrCircuitOpen, e.State)
+	}
+
+	return fmt.Sprintf("%v for %s (%s)", ErrCircuitOpen, e.Key, e.State)
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *BreakerOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreaker stops sending requests to the API after too many of
+// them failed, so that callers fail fast instead of waiting on timeouts.
+//
+// It is safe for concurrent use and can be shared between several
+// Sensibo instances, see WithCircuitBreaker.
+type CircuitBreaker struct {
+	config   BreakerConfig
+	now      func() time.Time
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state       BreakerState
+	generation  uint64
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	probes      int
+	successes   int
+}
+
+// NewCircuitBreaker creates a circuit breaker, zero config values use the defaults.
+func NewCircuitBreaker(config BreakerConfig) *CircuitBreaker {
+	if config.FailureRatio <= 0 {
+		config.FailureRatio = 0.5
+	}
+
+	if config.MinRequests < 1 {
+		config.MinRequests = 5
+	}
+
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+
+	if config.HalfOpenRequests < 1 {
+		config.HalfOpenRequests = 1
+	}
+
+	if config.IsFailure == nil {
+		config.IsFailure = isBreakerFailure
+	}
+
+	return &CircuitBreaker{
+		config:   config,
+		now:      time.Now,
+		circuits: map[string]*circuit{},
+	}
+}
+
+// State returns the current state of the breaker of operation,
+// operation is ignored unless PerEndpoint is set.
+func (b *CircuitBreaker) State(operation string) BreakerState {
+	b.mu.Lock()
+	c, changes := b.circuit(b.key(operation))
+	state := c.state
+	b.mu.Unlock()
+	b.notify(changes)
+
+	return state
+}
+
+// WithCircuitBreaker makes the client fail fast while breaker is open.
+//
+// Every attempt of a request, retries included, goes through the breaker.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(s *Sensibo) {
+		s.breaker = breaker
+	}
+}
+
+// doGuardedRequest makes a single attempt of the request through the circuit breaker.
+func (s *Sensibo) doGuardedRequest(ctx context.Context, r request, attempt int, payload []byte) error {
+	if s.breaker == nil {
+		return s.doRequest(ctx, r, attempt, payload)
+	}
+
+	key := r.op.name
+
+	if key == "Do" {
+		key = fmt.Sprintf("%s %s/%s", r.method, r.version, r.endpoint)
+	}
+
+	generation, err := s.breaker.allow(key)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.doRequest(ctx, r, attempt, payload)
+	s.breaker.record(key, generation, err)
+
+	return err
+}
+
+func (b *CircuitBreaker) key(operation string) string {
+	if !b.config.PerEndpoint {
+		return ""
+	}
+
+	return operation
+}
+
+// allow reports whether a request may be sent, it returns the
+// generation of the circuit to pass to record.
+func (b *CircuitBreaker) allow(operation string) (uint64, error) {
+	key := b.key(operation)
+
+	b.mu.Lock()
+	c, changes := b.circuit(key)
+
+	var err error
+
+	switch c.state {
+	case BreakerOpen:
+		err = &BreakerOpenError{
+			Key:        key,
+			State:      BreakerOpen,
+			RetryAfter: c.openedAt.Add(b.config.OpenTimeout).Sub(b.now()),
+		}
+	case BreakerHalfOpen:
+		if c.probes >= b.config.HalfOpenRequests {
+			err = &BreakerOpenError{Key: key, State: BreakerHalfOpen}
+		} else {
+			c.probes++
+		}
+	case BreakerClosed:
+	}
+
+	generation := c.generation
+	b.mu.Unlock()
+	b.notify(changes)
+
+	return generation, err
+}
+
+// record counts the outcome of a request allowed in generation.
+func (b *CircuitBreaker) record(operation string, generation uint64, err error) {
+	key := b.key(operation)
+
+	b.mu.Lock()
+	c, changes := b.circuit(key)
+
+	if c.generation != generation {
+		b.mu.Unlock()
+		b.notify(changes)
+
+		return
+	}
+
+	failed := err != nil && b.config.IsFailure(err)
+	ignored := err != nil && !failed && isContextError(err)
+
+	switch c.state {
+	case BreakerClosed:
+		if ignored {
+			break
+		}
+
+		c.requests++
+
+		if failed {
+			c.failures++
+		}
+
+		if c.requests >= b.config.MinRequests &&
+			float64(c.failures)/float64(c.requests) >= b.config.FailureRatio {
+			changes = append(changes, b.setState(key, c, BreakerOpen))
+		}
+	case BreakerHalfOpen:
+		switch {
+		case failed:
+			changes = append(changes, b.setState(key, c, BreakerOpen))
+		case ignored:
+			c.probes--
+		default:
+			c.successes++
+
+			if c.successes >= b.config.HalfOpenRequests {
+				changes = append(changes, b.setState(key, c, BreakerClosed))
+			}
+		}
+	case BreakerOpen:
+	}
+
+	b.mu.Unlock()
+	b.notify(changes)
+}
+
+// circuit returns the circuit of key after applying time based transitions.
+// It must be called with mu held.
+func (b *CircuitBreaker) circuit(key string) (*circuit, []BreakerStateChange) {
+	now := b.now()
+	c, ok := b.circuits[key]
+
+	if !ok {
+		c = &circuit{windowStart: now}
+		b.circuits[key] = c
+	}
+
+	var changes []BreakerStateChange
+
+	switch c.state {
+	case BreakerClosed:
+		if now.Sub(c.windowStart) >= b.config.Window {
+			c.windowStart = now
+			c.requests = 0
+			c.failures = 0
+		}
+	case BreakerOpen:
+		if now.Sub(c.openedAt) >= b.config.OpenTimeout {
+			changes = append(changes, b.setState(key, c, BreakerHalfOpen))
+		}
+	case BreakerHalfOpen:
+	}
+
+	return c, changes
+}
+
+// setState moves c to state and starts a new generation.
+// It must be called with mu held.
+func (b *CircuitBreaker) setState(key string, c *circuit, state BreakerState) BreakerStateChange {
+	change := BreakerStateChange{Key: key, From: c.state, To: state}
+	now := b.now()
+
+	c.state = state
+	c.generation++
+	c.windowStart = now
+	c.requests = 0
+	c.failures = 0
+	c.probes = 0
+	c.successes = 0
+
+	if state == BreakerOpen {
+		c.openedAt = now
+	}
+
+	return change
+}
+
+func (b *CircuitBreaker) notify(changes []BreakerStateChange) {
+	if b.config.OnStateChange == nil {
+		return
+	}
+
+	for _, change := range changes {
+		b.config.OnStateChange(change)
+	}
+}
+
+func isBreakerFailure(err error) bool {
+	var apiErr *APIError
+
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	if apiErr.Err != nil {
+		return !isContextError(apiErr.Err)
+	}
+
+	return apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,205 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(config BreakerConfig) (*CircuitBreaker, *fakeClock, *[]BreakerStateChange) {
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	changes := []BreakerStateChange{}
+
+	config.OnStateChange = func(change BreakerStateChange) {
+		changes = append(changes, change)
+	}
+
+	b := NewCircuitBreaker(config)
+	b.now = clock.Now
+
+	return b, clock, &changes
+}
+
+func TestCircuitBreaker_opensAndRecovers(t *testing.T) {
+	b, clock, changes := newTestBreaker(BreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		OpenTimeout:  10 * time.Second,
+	})
+	codes := []int{200, 500, 200, 503, 200, 200}
+	s := newStatusClient(codes, WithCircuitBreaker(b))
+
+	for i := 0; i < 4; i++ {
+		_, _ = s.GetDeviceTimer(context.Background(), "1234")
+	}
+
+	assert.Equal(t, BreakerOpen, b.State(""))
+
+	_, err := s.GetDeviceTimer(context.Background(), "1234")
+
+	var openErr *BreakerOpenError
+
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.True(t, errors.As(err, &openErr))
+	assert.Equal(t, 10*time.Second, openErr.RetryAfter)
+	assert.EqualError(t, err, "failed getting timer \n\tsensibo: circuit breaker open (open)")
+
+	clock.now = clock.now.Add(10 * time.Second)
+
+	assert.Equal(t, BreakerHalfOpen, b.State(""))
+
+	_, err = s.GetDeviceTimer(context.Background(), "1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, b.State(""))
+	assert.Equal(t, []BreakerStateChange{
+		{From: BreakerClosed, To: BreakerOpen},
+		{From: BreakerOpen, To: BreakerHalfOpen},
+		{From: BreakerHalfOpen, To: BreakerClosed},
+	}, *changes)
+}
+
+func TestCircuitBreaker_halfOpenFailureReopens(t *testing.T) {
+	b, clock, changes := newTestBreaker(BreakerConfig{MinRequests: 1})
+	s := newStatusClient([]int{500, 502}, WithCircuitBreaker(b))
+
+	_, _ = s.GetDeviceTimer(context.Background(), "1234")
+
+	clock.now = clock.now.Add(30 * time.Second)
+
+	_, err := s.GetDeviceTimer(context.Background(), "1234")
+
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Equal(t, BreakerOpen, b.State(""))
+	assert.Equal(t, []BreakerStateChange{
+		{From: BreakerClosed, To: BreakerOpen},
+		{From: BreakerOpen, To: BreakerHalfOpen},
+		{From: BreakerHalfOpen, To: BreakerOpen},
+	}, *changes)
+}
+
+func TestCircuitBreaker_halfOpenLimitsProbes(t *testing.T) {
+	b, clock, _ := newTestBreaker(BreakerConfig{MinRequests: 1, HalfOpenRequests: 2})
+
+	_, err := b.allow("")
+	assert.NoError(t, err)
+	b.record("", 0, &APIError{StatusCode: 500})
+
+	clock.now = clock.now.Add(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_, err = b.allow("")
+		assert.NoError(t, err)
+	}
+
+	_, err = b.allow("")
+	assert.EqualError(t, err, "sensibo: circuit breaker open (half-open)")
+}
+
+func TestCircuitBreaker_stateChangeCallbackReadsState(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	states := []BreakerState{}
+
+	var b *CircuitBreaker
+
+	b = NewCircuitBreaker(BreakerConfig{
+		MinRequests: 1,
+		OnStateChange: func(change BreakerStateChange) {
+			states = append(states, b.State(change.Key))
+		},
+	})
+	b.now = clock.Now
+
+	_, err := b.allow("")
+	assert.NoError(t, err)
+	b.record("", 0, &APIError{StatusCode: 500})
+
+	clock.now = clock.now.Add(time.Minute)
+
+	done := make(chan BreakerState)
+
+	go func() {
+		done <- b.State("")
+	}()
+
+	select {
+	case state := <-done:
+		assert.Equal(t, BreakerHalfOpen, state)
+	case <-time.After(time.Second):
+		t.Fatal("State deadlocked in OnStateChange")
+	}
+
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen}, states)
+}
+
+func TestCircuitBreaker_ignoresClientErrors(t *testing.T) {
+	b, _, _ := newTestBreaker(BreakerConfig{MinRequests: 1})
+
+	for _, err := range []error{
+		&APIError{StatusCode: 404},
+		&APIError{StatusCode: 429},
+		&APIError{Err: context.Canceled},
+		nil,
+	} {
+		generation, allowErr := b.allow("")
+
+		assert.NoError(t, allowErr)
+		b.record("", generation, err)
+	}
+
+	assert.Equal(t, BreakerClosed, b.State(""))
+}
+
+func TestCircuitBreaker_windowResetsCounts(t *testing.T) {
+	b, clock, _ := newTestBreaker(BreakerConfig{MinRequests: 2, Window: time.Second})
+
+	b.record("", 0, &APIError{StatusCode: 500})
+	clock.now = clock.now.Add(time.Second)
+	b.record("", 0, &APIError{StatusCode: 500})
+
+	assert.Equal(t, BreakerClosed, b.State(""))
+
+	b.record("", 0, &APIError{StatusCode: 500})
+
+	assert.Equal(t, BreakerOpen, b.State(""))
+}
+
+func TestCircuitBreaker_perEndpoint(t *testing.T) {
+	b, _, changes := newTestBreaker(BreakerConfig{MinRequests: 1, PerEndpoint: true})
+	s := newStatusClient([]int{500, 200}, WithCircuitBreaker(b))
+
+	_, err := s.GetDeviceTimer(context.Background(), "1234")
+	assert.True(t, errors.Is(err, ErrServer))
+
+	_, err = s.GetDeviceTimer(context.Background(), "1234")
+	assert.EqualError(t, err, "failed getting timer \n\tsensibo: circuit breaker open for GetDeviceTimer (open)")
+
+	_, err = s.GetDeviceClimateReactSettings(context.Background(), "1234")
+	assert.NoError(t, err)
+
+	assert.Equal(t, BreakerOpen, b.State("GetDeviceTimer"))
+	assert.Equal(t, BreakerClosed, b.State("GetDeviceClimateReactSettings"))
+	assert.Equal(t, []BreakerStateChange{{Key: "GetDeviceTimer", From: BreakerClosed, To: BreakerOpen}}, *changes)
+}
+
+func TestBreakerState_String(t *testing.T) {
+	assert.Equal(t, "closed", BreakerClosed.String())
+	assert.Equal(t, "open", BreakerOpen.String())
+	assert.Equal(t, "half-open", BreakerHalfOpen.String())
+	assert.Equal(t, "BreakerState(7)", BreakerState(7).String())
+}
@@ -0,0 +1,298 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore stores cached results of GET requests.
+//
+// Keys of entries that belong to a device start with "<device ID>|",
+// keys of entries that don't belong to any device start with "|".
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the value stored for key, if it exists and has not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores value for key for ttl.
+	Set(key string, value []byte, ttl time.Duration)
+	// DeletePrefix deletes all the entries whose key starts with prefix.
+	DeletePrefix(prefix string)
+}
+
+// CacheConfig configures caching of GET requests.
+//
+// Cached entries of a device are invalidated whenever a request that
+// changes it (e.g: SetDeviceACState, CreateDeviceSchedule) succeeds,
+// entries that list devices (e.g: GetAllDevices) are invalidated on every change.
+//
+// Entries are not keyed by API key, do not share a store between accounts.
+type CacheConfig struct {
+	// Store holds the cached entries, e.g: NewLRUCache(1000).
+	Store CacheStore
+	// DefaultTTL is used for operations missing from TTLs.
+	DefaultTTL time.Duration
+	// TTLs holds TTLs by operation name (e.g: "GetDevice"),
+	// a TTL of 0 or less disables caching of the operation.
+	TTLs map[string]time.Duration
+}
+
+// WithCache enables caching of GET requests.
+func WithCache(config CacheConfig) Option {
+	return func(s *Sensibo) {
+		s.cache = &responseCache{config: config, devices: map[string]uint64{}}
+	}
+}
+
+type responseCache struct {
+	config CacheConfig
+
+	// mu orders invalidations with storing results, the generations
+	// are bumped on every invalidation so results of GET requests that
+	// were in flight during a change are not stored.
+	mu      sync.Mutex
+	changes uint64
+	resets  uint64
+	devices map[string]uint64
+}
+
+func (c *responseCache) ttl(op operation) time.Duration {
+	if ttl, ok := c.config.TTLs[op.name]; ok {
+		return ttl
+	}
+
+	return c.config.DefaultTTL
+}
+
+// requestKey identifies a request by its endpoint and query parameters.
+func requestKey(r request) string {
+	keys := make([]string, 0, len(r.params))
+
+	for k := range r.params {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	query := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		query = append(query, fmt.Sprintf("%s=%s", k, url.QueryEscape(r.params[k])))
+	}
+
+	return fmt.Sprintf("%s|%s/%s?%s", r.op.deviceID, r.version, r.endpoint, strings.Join(query, "&"))
+}
+
+func (c *responseCache) invalidate(deviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.changes++
+
+	if deviceID == "" {
+		c.resets++
+		c.config.Store.DeletePrefix("")
+
+		return
+	}
+
+	c.devices[deviceID]++
+	c.config.Store.DeletePrefix(fmt.Sprintf("%s|", deviceID))
+	c.config.Store.DeletePrefix("|")
+}
+
+// generation changes whenever entries of deviceID are invalidated,
+// entries that don't belong to any device are invalidated on every change.
+func (c *responseCache) generation(deviceID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generationLocked(deviceID)
+}
+
+func (c *responseCache) generationLocked(deviceID string) uint64 {
+	if deviceID == "" {
+		return c.changes
+	}
+
+	return c.resets + c.devices[deviceID]
+}
+
+// set stores value unless the entries of deviceID were invalidated
+// since generation was taken.
+func (c *responseCache) set(deviceID string, generation uint64, key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generationLocked(deviceID) != generation {
+		return
+	}
+
+	c.config.Store.Set(key, value, ttl)
+}
+
+// makeCachedRequest serves GET requests from the cache when possible and
+// invalidates cached entries after successful changes.
+func (s *Sensibo) makeCachedRequest(ctx context.Context, r request, payload []byte) error {
+	if s.cache == nil || s.cache.config.Store == nil {
+		return s.makeCoalescedRequest(ctx, r, payload)
+	}
+
+	if r.method != http.MethodGet {
+		if err := s.makeAttempts(ctx, r, payload); err != nil {
+			return err
+		}
+
+		s.cache.invalidate(r.op.deviceID)
+
+		return nil
+	}
+
+	ttl := s.cache.ttl(r.op)
+
+	if ttl <= 0 {
+		return s.makeCoalescedRequest(ctx, r, payload)
+	}
+
+	key := requestKey(r)
+
+	if cached, ok := s.cache.config.Store.Get(key); ok {
+		return decodeCached(cached, r.result)
+	}
+
+	generation := s.cache.generation(r.op.deviceID)
+	raw := json.RawMessage{}
+	result := r.result
+	r.result = &raw
+
+	if err := s.makeCoalescedRequest(ctx, r, payload); err != nil {
+		return err
+	}
+
+	if err := decodeCached(raw, result); err != nil {
+		return err
+	}
+
+	if len(raw) > 0 {
+		s.cache.set(r.op.deviceID, generation, key, raw, ttl)
+	}
+
+	return nil
+}
+
+func decodeCached(raw []byte, result interface{}) error {
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, result); err != nil {
+		return &DecodeError{Body: string(raw), Err: err}
+	}
+
+	return nil
+}
+
+// LRUCache is an in-memory CacheStore that evicts the least recently
+// used entries once it holds more than its capacity.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache creates an LRUCache holding up to capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Get returns the value stored for key, if it exists and has not expired.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+
+	if time.Now().After(entry.expires) {
+		c.remove(el)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return entry.value, true
+}
+
+// Set stores value for key for ttl.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = time.Now().Add(ttl)
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key, value, time.Now().Add(ttl)})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// DeletePrefix deletes all the entries whose key starts with prefix.
+func (c *LRUCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+		}
+	}
+}
+
+// Len returns the number of entries in the cache, including expired ones.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
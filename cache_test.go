@@ -0,0 +1,175 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCountingClient(opts ...Option) (*Sensibo, map[string]int) {
+	calls := map[string]int{}
+
+	return newMockClient(func(req *http.Request) (*http.Response, error) {
+		calls[fmt.Sprintf("%s %s", req.Method, req.URL.Path)]++
+
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1234"}}`)),
+			StatusCode: 200,
+		}, nil
+	}, opts...), calls
+}
+
+func TestWithCache_servesRepeatedGets(t *testing.T) {
+	s, calls := newCountingClient(WithCache(CacheConfig{
+		Store:      NewLRUCache(10),
+		DefaultTTL: time.Minute,
+	}))
+
+	for i := 0; i < 3; i++ {
+		got, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &models.Device{ID: "1234"}, got)
+	}
+
+	assert.Equal(t, 1, calls["GET /api/v2/pods/1234"])
+
+	// different fields are cached separately
+	_, err := s.GetDevice(context.Background(), "1234", []string{"id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls["GET /api/v2/pods/1234"])
+}
+
+func TestWithCache_perOperationTTL(t *testing.T) {
+	s, calls := newCountingClient(WithCache(CacheConfig{
+		Store:      NewLRUCache(10),
+		DefaultTTL: time.Minute,
+		TTLs:       map[string]time.Duration{"GetDeviceTimer": 0},
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err := s.GetDeviceTimer(context.Background(), "1234")
+
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, calls["GET /api/v1/pods/1234/timer"])
+}
+
+func TestWithCache_invalidatesDeviceOnWrite(t *testing.T) {
+	s, calls := newCountingClient(WithCache(CacheConfig{
+		Store:      NewLRUCache(10),
+		DefaultTTL: time.Minute,
+	}))
+
+	_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+	assert.NoError(t, err)
+
+	_, err = s.GetDevice(context.Background(), "5678", []string{"*"})
+	assert.NoError(t, err)
+
+	err = s.DeleteDeviceTimer(context.Background(), "1234")
+	assert.NoError(t, err)
+
+	_, err = s.GetDevice(context.Background(), "1234", []string{"*"})
+	assert.NoError(t, err)
+
+	_, err = s.GetDevice(context.Background(), "5678", []string{"*"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, calls["GET /api/v2/pods/1234"])
+	assert.Equal(t, 1, calls["GET /api/v2/pods/5678"])
+}
+
+func TestWithCache_doesNotStoreGetsRacingWrites(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	gets := 0
+
+	s := NewClient(
+		"api-key",
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet {
+					gets++
+
+					if gets == 1 {
+						close(started)
+						<-release
+					}
+				}
+
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1234"}}`)),
+					StatusCode: 200,
+				}, nil
+			},
+		}),
+		WithCache(CacheConfig{Store: NewLRUCache(10), DefaultTTL: time.Minute}),
+	)
+
+	done := make(chan error)
+
+	go func() {
+		_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+		done <- err
+	}()
+
+	<-started
+
+	_, err := s.SetDeviceACStateProperty(context.Background(), "1234", "on", "true")
+
+	assert.NoError(t, err)
+
+	close(release)
+
+	assert.NoError(t, <-done)
+
+	// the result fetched before the change must not be served from the cache
+	_, err = s.GetDevice(context.Background(), "1234", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, gets)
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	// "b" is the least recently used entry
+	c.Set("c", []byte("3"), time.Minute)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	c.Set("d", []byte("4"), -time.Second)
+
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+
+	c.Set("1234|a", []byte("1"), time.Minute)
+	c.Set("1234|b", []byte("1"), time.Minute)
+	c.DeletePrefix("1234|")
+
+	_, ok = c.Get("1234|b")
+	assert.False(t, ok)
+}
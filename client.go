@@ -0,0 +1,46 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+// Client is the Sensibo API surface, it is implemented by *Sensibo.
+//
+// Depend on Client instead of *Sensibo to be able to substitute
+// mocks.ClientMock in unit tests.
+type Client interface {
+	GetAllDevices(ctx context.Context, fields []string) ([]models.Device, error)
+	GetDevice(ctx context.Context, id string, fields []string) (*models.Device, error)
+	GetDeviceACStates(ctx context.Context, id string, limit uint) ([]models.ACState, error)
+	GetDeviceHistoricalMeasurements(ctx context.Context, id string, days uint) (*models.HistoricalMeasurements, error)
+	GetDeviceClimateReactSettings(ctx context.Context, id string) (*models.ClimateReact, error)
+	GetDeviceTimer(ctx context.Context, id string) (*models.DeviceTimer, error)
+	GetDeviceSchedules(ctx context.Context, id string) ([]models.DeviceSchedule, error)
+	GetDeviceSchedule(ctx context.Context, deviceID string, scheduleID string) (*models.DeviceSchedule, error)
+	SetDeviceACStateProperty(ctx context.Context, id string, property string, value string) (*models.ACState, error)
+	SetDeviceACState(ctx context.Context, id string, state models.ACStateData) (*models.ACState, error)
+	UpdateDeviceACState(ctx context.Context, id string, patch models.ACStatePatch) (*models.ACState, error)
+	CreateDeviceSchedule(ctx context.Context, id string, schedule CreateDeviceSchedulePayload) (*models.DeviceSchedule, error)
+	SetDeviceTimer(ctx context.Context, id string, minutesFromNow int, state models.ACStateData) (*models.DeviceTimer, error)
+	ToggleDeviceClimateReact(ctx context.Context, id string, enabled bool) (*models.ClimateReact, error)
+	ToggleDeviceSchedule(ctx context.Context, deviceID string, scheduleID string, enabled bool) (*models.DeviceSchedule, error)
+	DeleteDeviceTimer(ctx context.Context, id string) error
+	DeleteDeviceSchedule(ctx context.Context, deviceID string, scheduleID string) error
+	Do(
+		ctx context.Context,
+		method string,
+		version string,
+		endpoint string,
+		query map[string]string,
+		body interface{},
+		out interface{},
+	) error
+}
+
+var _ Client = (*Sensibo)(nil)
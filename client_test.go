@@ -0,0 +1,51 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Client = (*mocks.ClientMock)(nil)
+
+func TestClientMock(t *testing.T) {
+	m := &mocks.ClientMock{
+		GetDeviceMock: func(ctx context.Context, id string, fields []string) (*models.Device, error) {
+			return &models.Device{ID: id}, nil
+		},
+	}
+
+	var c Client = m
+
+	got, err := c.GetDevice(context.Background(), "1234", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Device{ID: "1234"}, got)
+
+	err = c.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.EqualError(t, err, "mocks: DeleteDeviceTimer is not stubbed")
+
+	_, err = c.CreateDeviceSchedule(context.Background(), "1234", CreateDeviceSchedulePayload{TimeZone: "UTC"})
+
+	assert.EqualError(t, err, "mocks: CreateDeviceSchedule is not stubbed")
+	assert.Equal(t, []mocks.Call{
+		{Method: "GetDevice", Args: []interface{}{"1234", []string{"*"}}},
+		{Method: "DeleteDeviceTimer", Args: []interface{}{"1234"}},
+		{Method: "CreateDeviceSchedule", Args: []interface{}{"1234", models.CreateDeviceSchedulePayload{TimeZone: "UTC"}}},
+	}, m.Calls())
+	assert.Equal(t, []mocks.Call{
+		{Method: "DeleteDeviceTimer", Args: []interface{}{"1234"}},
+	}, m.CallsTo("DeleteDeviceTimer"))
+
+	m.Reset()
+
+	assert.Empty(t, m.Calls())
+}
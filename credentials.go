@@ -0,0 +1,146 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoAPIKey is returned when a credential provider has no API key to give.
+var ErrNoAPIKey = errors.New("sensibo: no API key")
+
+// CredentialProvider provides the API key used to authenticate requests.
+//
+// APIKey is called before every request so implementations can rotate
+// the key at any time. It must be safe for concurrent use.
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// CredentialsFunc is an adapter to allow the use of ordinary functions
+// as a CredentialProvider.
+type CredentialsFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f(ctx).
+func (f CredentialsFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithCredentials sets the provider of the API key used by the client.
+func WithCredentials(provider CredentialProvider) Option {
+	return func(s *Sensibo) {
+		s.credentials = provider
+	}
+}
+
+type staticCredentials struct {
+	apiKey string
+}
+
+// StaticCredentials returns a provider that always returns apiKey.
+func StaticCredentials(apiKey string) CredentialProvider {
+	return staticCredentials{apiKey}
+}
+
+func (c staticCredentials) APIKey(ctx context.Context) (string, error) {
+	if c.apiKey == "" {
+		return "", ErrNoAPIKey
+	}
+
+	return c.apiKey, nil
+}
+
+func (c staticCredentials) String() string {
+	return "StaticCredentials(" + redacted + ")"
+}
+
+func (c staticCredentials) GoString() string {
+	return c.String()
+}
+
+type envCredentials struct {
+	name string
+}
+
+// EnvCredentials returns a provider that reads the API key from the
+// environment variable name on every request.
+func EnvCredentials(name string) CredentialProvider {
+	return envCredentials{name}
+}
+
+func (c envCredentials) APIKey(ctx context.Context) (string, error) {
+	apiKey := strings.TrimSpace(os.Getenv(c.name))
+
+	if apiKey == "" {
+		return "", fmt.Errorf("environment variable %s is empty: \n\t%w", c.name, ErrNoAPIKey)
+	}
+
+	return apiKey, nil
+}
+
+type fileCredentials struct {
+	path    string
+	mu      sync.Mutex
+	apiKey  string
+	modTime time.Time
+	size    int64
+}
+
+// FileCredentials returns a provider that reads the API key from the file
+// at path. The file is read again whenever its modification time or size
+// changes, so the key can be rotated by rewriting the file.
+//
+// Leading and trailing whitespace in the file is ignored.
+func FileCredentials(path string) CredentialProvider {
+	return &fileCredentials{path: path}
+}
+
+func (c *fileCredentials) APIKey(ctx context.Context) (string, error) {
+	info, err := os.Stat(c.path)
+
+	if err != nil {
+		return "", fmt.Errorf("failed reading API key file: \n\t%w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.apiKey != "" && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.apiKey, nil
+	}
+
+	content, err := ioutil.ReadFile(c.path)
+
+	if err != nil {
+		return "", fmt.Errorf("failed reading API key file: \n\t%w", err)
+	}
+
+	apiKey := strings.TrimSpace(string(content))
+
+	if apiKey == "" {
+		return "", fmt.Errorf("file %s is empty: \n\t%w", c.path, ErrNoAPIKey)
+	}
+
+	c.apiKey = apiKey
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+
+	return c.apiKey, nil
+}
+
+func (c *fileCredentials) String() string {
+	return "FileCredentials(" + c.path + ")"
+}
+
+func (c *fileCredentials) GoString() string {
+	return c.String()
+}
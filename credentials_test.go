@@ -0,0 +1,115 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticCredentials(t *testing.T) {
+	got, err := StaticCredentials("api-key").APIKey(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "api-key", got)
+
+	_, err = StaticCredentials("").APIKey(context.Background())
+
+	assert.True(t, errors.Is(err, ErrNoAPIKey))
+	assert.NotContains(t, fmt.Sprintf("%v %#v", StaticCredentials("api-key"), StaticCredentials("api-key")), "api-key")
+}
+
+func TestEnvCredentials(t *testing.T) {
+	os.Setenv("GO_SENSIBO_TEST_KEY", " env-key\n")
+	defer os.Unsetenv("GO_SENSIBO_TEST_KEY")
+
+	got, err := EnvCredentials("GO_SENSIBO_TEST_KEY").APIKey(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "env-key", got)
+
+	_, err = EnvCredentials("GO_SENSIBO_TEST_MISSING").APIKey(context.Background())
+
+	assert.True(t, errors.Is(err, ErrNoAPIKey))
+}
+
+func TestFileCredentials_reloadsOnChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-sensibo")
+	assert.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "key")
+	provider := FileCredentials(path)
+
+	_, err = provider.APIKey(context.Background())
+	assert.Error(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("first-key\n"), 0600))
+
+	got, err := provider.APIKey(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "first-key", got)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("second-key-longer\n"), 0600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+
+	got, err = provider.APIKey(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "second-key-longer", got)
+}
+
+func TestWithCredentials_consultedPerRequest(t *testing.T) {
+	keys := []string{}
+	current := "first-key"
+
+	s := NewClient(
+		"",
+		WithCredentials(CredentialsFunc(func(ctx context.Context) (string, error) {
+			return current, nil
+		})),
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				keys = append(keys, req.URL.Query().Get("apiKey"))
+
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
+					StatusCode: 200,
+				}, nil
+			},
+		}),
+	)
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+	assert.NoError(t, err)
+
+	current = "second-key"
+
+	err = s.DeleteDeviceTimer(context.Background(), "1234")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"first-key", "second-key"}, keys)
+}
+
+func TestWithCredentials_providerError(t *testing.T) {
+	s := NewClient("", WithHTTPClient(&mocks.HTTPClientMock{}))
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.True(t, errors.Is(err, ErrNoAPIKey))
+}
@@ -13,36 +13,38 @@ import (
 //
 // id is the ID of the device
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) DeleteDeviceTimer(ctx context.Context, id string) (string, error) {
-	resp, err := s.makeDeleteRequest(
+// It returns an error if an issue occurred
+func (s *Sensibo) DeleteDeviceTimer(ctx context.Context, id string) error {
+	err := s.makeDeleteRequest(
 		ctx,
+		operation{"DeleteDeviceTimer", id},
 		"v1",
 		fmt.Sprintf("pods/%s/timer", id),
+		nil,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed deleting timer: \n\t%v", err)
+		return fmt.Errorf("failed deleting timer: \n\t%w", err)
 	}
 
-	return resp, nil
+	return nil
 }
 
 // DeleteDeviceSchedule deletes a schedule.
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) DeleteDeviceSchedule(ctx context.Context, deviceID string, scheduleID string) (string, error) {
-	resp, err := s.makeDeleteRequest(
+// It returns an error if an issue occurred
+func (s *Sensibo) DeleteDeviceSchedule(ctx context.Context, deviceID string, scheduleID string) error {
+	err := s.makeDeleteRequest(
 		ctx,
+		operation{"DeleteDeviceSchedule", deviceID},
 		"v1",
 		fmt.Sprintf("pods/%s/schedules/%s", deviceID, scheduleID),
+		nil,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed deleting schedule: \n\t%v", err)
+		return fmt.Errorf("failed deleting schedule: \n\t%w", err)
 	}
 
-	return resp, nil
+	return nil
 }
@@ -25,8 +25,7 @@ func TestSensibo_DeleteDeviceTimer(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		err    string
 	}{
 		{
 			name: "returns api request response on success",
@@ -37,11 +36,10 @@ func TestSensibo_DeleteDeviceTimer(t *testing.T) {
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
 					StatusCode: 200,
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			err: "",
 		},
 		{
 			name: "returns err on error",
@@ -52,11 +50,10 @@ func TestSensibo_DeleteDeviceTimer(t *testing.T) {
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
 					StatusCode: 200,
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed deleting timer: \n\tfailed making request \n\tCode: 200 \n\tMsg: Success \n\tErr: Error"),
+			err: "failed deleting timer: \n\tsensibo: DELETE v1/pods/1234/timer: Error",
 		},
 	}
 
@@ -69,10 +66,13 @@ func TestSensibo_DeleteDeviceTimer(t *testing.T) {
 				"api-key",
 			)
 
-			got, err := s.DeleteDeviceTimer(tt.args.ctx, tt.args.id)
+			err := s.DeleteDeviceTimer(tt.args.ctx, tt.args.id)
 
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
@@ -87,8 +87,7 @@ func TestSensibo_DeleteDeviceSchedule(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		err    string
 	}{
 		{
 			name: "returns api request response on success",
@@ -100,11 +99,10 @@ func TestSensibo_DeleteDeviceSchedule(t *testing.T) {
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
 					StatusCode: 200,
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			err: "",
 		},
 		{
 			name: "returns err on error",
@@ -116,11 +114,10 @@ func TestSensibo_DeleteDeviceSchedule(t *testing.T) {
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
 					StatusCode: 200,
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed deleting schedule: \n\tfailed making request \n\tCode: 200 \n\tMsg: Success \n\tErr: Error"),
+			err: "failed deleting schedule: \n\tsensibo: DELETE v1/pods/1234/schedules/1234: Error",
 		},
 	}
 	for _, tt := range tests {
@@ -132,10 +129,13 @@ func TestSensibo_DeleteDeviceSchedule(t *testing.T) {
 				"api-key",
 			)
 
-			got, err := s.DeleteDeviceSchedule(tt.args.ctx, tt.args.deviceID, tt.args.scheduleID)
+			err := s.DeleteDeviceSchedule(tt.args.ctx, tt.args.deviceID, tt.args.scheduleID)
 
-			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
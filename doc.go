@@ -11,11 +11,15 @@ You can run the following simple code to get you started:
 
 	package main
 
-	import "github.com/odinn1984/go-sensibo"
+	import (
+		"context"
+
+		"github.com/odinn1984/go-sensibo"
+	)
 
 	func main() {
-		client := sensibo.New("my-api-key")
-		devices, err := client.GetAllDevices([]string{"*"})
+		client := sensibo.NewClient("my-api-key")
+		devices, err := client.GetAllDevices(context.Background(), []string{"*"})
 
 		if err != nil {
 			// Do some error handling
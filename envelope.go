@@ -0,0 +1,152 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// statusSuccess is the envelope status of successful responses.
+const statusSuccess = "success"
+
+var (
+	// ErrUnsuccessfulStatus is matched by errors returned when the response
+	// envelope has a status other than "success".
+	ErrUnsuccessfulStatus = errors.New("sensibo: unsuccessful response status")
+	// ErrEmptyResult is matched by errors returned when the response
+	// envelope has no result although one is required.
+	ErrEmptyResult = errors.New("sensibo: empty result")
+)
+
+// StatusError is returned when the API responds with a status other than
+// "success" in the response envelope.
+type StatusError struct {
+	Status  string
+	Reason  string
+	Message string
+	// Body holds the beginning of the raw body of the response.
+	Body string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("sensibo: response status %q", e.Status)
+
+	if e.Reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Reason)
+	}
+
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+
+	return msg
+}
+
+// Is reports whether target is ErrUnsuccessfulStatus.
+func (e *StatusError) Is(target error) bool {
+	return target == ErrUnsuccessfulStatus
+}
+
+// DecodeError is returned when the response envelope can't be decoded.
+type DecodeError struct {
+	// Body holds the beginning of the raw body of the response.
+	Body string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("sensibo: failed decoding response: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// maxDiagnosticBody is the number of leading response bytes kept
+// in errors for diagnostics.
+const maxDiagnosticBody = 4 << 10
+
+// decodeEnvelope decodes a {"status": ..., "result": ...} response envelope
+// straight from body, storing the result in the value pointed to by result.
+//
+// When required is set it fails with ErrEmptyResult if the envelope has no result.
+func decodeEnvelope(body io.Reader, result interface{}, required bool) error {
+	diagnostics := &prefixBuffer{max: maxDiagnosticBody}
+	envelope := struct {
+		Status  string
+		Reason  string
+		Message string
+		Result  interface{}
+	}{}
+
+	// result is decoded through a nil pointer to it so that a missing or
+	// null result can be told apart from a present one
+	var holder reflect.Value
+
+	if result != nil {
+		holder = reflect.New(reflect.TypeOf(result))
+		envelope.Result = holder.Interface()
+	}
+
+	if err := json.NewDecoder(io.TeeReader(body, diagnostics)).Decode(&envelope); err != nil {
+		return &DecodeError{Body: diagnostics.String(), Err: err}
+	}
+
+	if !strings.EqualFold(envelope.Status, statusSuccess) {
+		return &StatusError{
+			Status:  envelope.Status,
+			Reason:  envelope.Reason,
+			Message: envelope.Message,
+			Body:    diagnostics.String(),
+		}
+	}
+
+	present := envelope.Result != nil
+
+	if result != nil {
+		present = !holder.Elem().IsNil()
+
+		if present {
+			reflect.ValueOf(result).Elem().Set(holder.Elem().Elem())
+		}
+	}
+
+	if required && !present {
+		return &DecodeError{Body: diagnostics.String(), Err: ErrEmptyResult}
+	}
+
+	return nil
+}
+
+// prefixBuffer keeps the first max bytes written to it.
+type prefixBuffer struct {
+	max int
+	buf bytes.Buffer
+}
+
+func (b *prefixBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (b *prefixBuffer) String() string {
+	return b.buf.String()
+}
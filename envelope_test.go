@@ -0,0 +1,104 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     string
+		required bool
+		want     models.DeviceTimer
+		err      string
+		target   error
+	}{
+		{
+			name: "decodes result on success",
+			resp: `{"status":"success","result":{"id":"1","isEnabled":true}}`,
+			want: models.DeviceTimer{ID: "1", IsEnabled: true},
+		},
+		{
+			name:   "returns status error",
+			resp:   `{"status":"failed","reason":"Busy","message":"try later"}`,
+			err:    `sensibo: response status "failed": Busy: try later`,
+			target: ErrUnsuccessfulStatus,
+		},
+		{
+			name: "returns decode error on invalid json",
+			resp: `not json`,
+			err:  "sensibo: failed decoding response: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name: "keeps only the beginning of the body for diagnostics",
+			resp: `{"status":"failed","message":"` + strings.Repeat("a", maxDiagnosticBody) + `"}`,
+			err:  `sensibo: response status "failed": ` + strings.Repeat("a", maxDiagnosticBody),
+		},
+		{
+			name: "allows missing result when not required",
+			resp: `{"status":"success"}`,
+		},
+		{
+			name:     "fails on missing result when required",
+			resp:     `{"status":"success"}`,
+			required: true,
+			err:      "sensibo: failed decoding response: sensibo: empty result",
+			target:   ErrEmptyResult,
+		},
+		{
+			name:     "fails on null result when required",
+			resp:     `{"status":"success","result":null}`,
+			required: true,
+			err:      "sensibo: failed decoding response: sensibo: empty result",
+			target:   ErrEmptyResult,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := models.DeviceTimer{}
+			err := decodeEnvelope(strings.NewReader(tt.resp), &got, tt.required)
+
+			assert.Equal(t, tt.want, got)
+
+			if tt.target != nil {
+				assert.True(t, errors.Is(err, tt.target))
+			}
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.EqualError(t, err, tt.err)
+
+			var statusErr *StatusError
+			var decodeErr *DecodeError
+
+			body := tt.resp
+
+			if len(body) > maxDiagnosticBody {
+				body = body[:maxDiagnosticBody]
+			}
+
+			switch {
+			case errors.As(err, &statusErr):
+				assert.Equal(t, body, statusErr.Body)
+			case errors.As(err, &decodeErr):
+				assert.Equal(t, body, decodeErr.Body)
+			default:
+				t.Errorf("unexpected error type %T", err)
+			}
+		})
+	}
+}
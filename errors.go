@@ -0,0 +1,169 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors that can be matched with errors.Is against any error
+// returned by the client.
+var (
+	// ErrBadRequest is returned when the API rejects the request as invalid.
+	ErrBadRequest = errors.New("sensibo: bad request")
+	// ErrUnauthorized is returned when the API key is missing or invalid.
+	ErrUnauthorized = errors.New("sensibo: unauthorized")
+	// ErrForbidden is returned when the API key has no access to the resource.
+	ErrForbidden = errors.New("sensibo: forbidden")
+	// ErrNotFound is returned when the device or resource does not exist.
+	ErrNotFound = errors.New("sensibo: not found")
+	// ErrRateLimited is returned when the API throttled the request.
+	ErrRateLimited = errors.New("sensibo: rate limited")
+	// ErrServer is returned when the API responded with a 5xx status code.
+	ErrServer = errors.New("sensibo: server error")
+	// ErrDeviceOffline is returned when the device is not connected to Sensibo.
+	ErrDeviceOffline = errors.New("sensibo: device offline")
+)
+
+// ErrorResponse is the error body returned by the Sensibo API.
+type ErrorResponse struct {
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// APIError holds information about a failed call to the Sensibo API.
+//
+// It is returned (wrapped) by every method of Sensibo, use errors.As
+// to get to it.
+type APIError struct {
+	Method     string
+	Endpoint   string
+	Version    string
+	StatusCode int
+	// Response is the parsed error body, it is nil when the body
+	// was empty or not a Sensibo error body.
+	Response *ErrorResponse
+	// Body is the raw body of the response.
+	Body string
+	// RetryAfter is the delay requested by the API through
+	// the Retry-After header, zero if none was sent.
+	RetryAfter time.Duration
+	// Err is the underlying transport error, if any.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	prefix := fmt.Sprintf("sensibo: %s %s/%s", e.Method, e.Version, e.Endpoint)
+
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", prefix, e.Err)
+	}
+
+	msg := fmt.Sprintf("%s: %d %s", prefix, e.StatusCode, http.StatusText(e.StatusCode))
+
+	if e.Response != nil {
+		if e.Response.Reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, e.Response.Reason)
+		}
+
+		if e.Response.Message != "" {
+			msg = fmt.Sprintf("%s: %s", msg, e.Response.Message)
+		}
+	} else if e.Body != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Body)
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying transport error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrDeviceOffline:
+		return e.isDeviceOffline()
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// Retryable reports whether repeating the same request might succeed.
+//
+// Transport failures, rate limiting and 5xx responses are retryable,
+// cancelled or expired contexts are not.
+func (e *APIError) Retryable() bool {
+	if e.Err != nil {
+		return !errors.Is(e.Err, context.Canceled) && !errors.Is(e.Err, context.DeadlineExceeded)
+	}
+
+	switch e.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func (e *APIError) isDeviceOffline() bool {
+	if e.Response == nil {
+		return false
+	}
+
+	text := strings.ToLower(e.Response.Reason + " " + e.Response.Message)
+
+	for _, s := range []string{"offline", "not connected", "notconnected", "disconnected", "not alive"} {
+		if strings.Contains(text, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseErrorResponse(body []byte) *ErrorResponse {
+	if len(body) == 0 {
+		return nil
+	}
+
+	errResp := ErrorResponse{}
+
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return nil
+	}
+
+	if errResp.Status == "" && errResp.Reason == "" && errResp.Message == "" {
+		return nil
+	}
+
+	return &errResp
+}
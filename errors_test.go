@@ -0,0 +1,138 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{
+			name:   "unauthorized",
+			err:    &APIError{StatusCode: http.StatusUnauthorized},
+			target: ErrUnauthorized,
+			want:   true,
+		},
+		{
+			name:   "not found",
+			err:    &APIError{StatusCode: http.StatusNotFound},
+			target: ErrNotFound,
+			want:   true,
+		},
+		{
+			name:   "rate limited",
+			err:    &APIError{StatusCode: http.StatusTooManyRequests},
+			target: ErrRateLimited,
+			want:   true,
+		},
+		{
+			name:   "server error",
+			err:    &APIError{StatusCode: http.StatusBadGateway},
+			target: ErrServer,
+			want:   true,
+		},
+		{
+			name: "device offline",
+			err: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Response:   &ErrorResponse{Status: "error", Reason: "DeviceOffline"},
+			},
+			target: ErrDeviceOffline,
+			want:   true,
+		},
+		{
+			name:   "does not match different status",
+			err:    &APIError{StatusCode: http.StatusNotFound},
+			target: ErrUnauthorized,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func TestAPIError_Retryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		want bool
+	}{
+		{
+			name: "transport error",
+			err:  &APIError{Err: fmt.Errorf("connection reset")},
+			want: true,
+		},
+		{
+			name: "cancelled context",
+			err:  &APIError{Err: context.Canceled},
+			want: false,
+		},
+		{
+			name: "rate limited",
+			err:  &APIError{StatusCode: http.StatusTooManyRequests},
+			want: true,
+		},
+		{
+			name: "service unavailable",
+			err:  &APIError{StatusCode: http.StatusServiceUnavailable},
+			want: true,
+		},
+		{
+			name: "bad request",
+			err:  &APIError{StatusCode: http.StatusBadRequest},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Retryable())
+		})
+	}
+}
+
+func TestAPIError_unwrapsThroughPublicMethods(t *testing.T) {
+	s := New(
+		&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"error","reason":"Unauthorized"}`)),
+					StatusCode: http.StatusUnauthorized,
+				}, nil
+			},
+		},
+		"api-key",
+	)
+
+	_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+	var apiErr *APIError
+
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.MethodGet, apiErr.Method)
+	assert.Equal(t, "pods/1234", apiErr.Endpoint)
+	assert.Equal(t, "v2", apiErr.Version)
+	assert.Equal(t, "Unauthorized", apiErr.Response.Reason)
+}
@@ -6,8 +6,8 @@ package sensibo
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/odinn1984/go-sensibo/models"
@@ -20,27 +20,22 @@ import (
 //
 // e.g: To get all fields use "*" and to get "id" only use "id"
 func (s *Sensibo) GetAllDevices(ctx context.Context, fields []string) ([]models.Device, error) {
-	resp, err := s.makeGetRequest(
+	result := []models.Device{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetAllDevices", ""},
 		"v2",
 		"users/me/pods",
 		map[string]string{"fields": strings.Join(fields, ",")},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting all devices \n\t%v", err)
+		return nil, fmt.Errorf("failed getting all devices \n\t%w", err)
 	}
 
-	parsedResp := struct {
-		Status string
-		Result []models.Device
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
-	}
-
-	return parsedResp.Result, nil
+	return result, nil
 }
 
 // GetDevice gets a device by ID.
@@ -52,27 +47,22 @@ func (s *Sensibo) GetAllDevices(ctx context.Context, fields []string) ([]models.
 //
 // e.g: To get all fields use "*" and to get "id" only use "id"
 func (s *Sensibo) GetDevice(ctx context.Context, id string, fields []string) (*models.Device, error) {
-	resp, err := s.makeGetRequest(
+	result := models.Device{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetDevice", id},
 		"v2",
 		fmt.Sprintf("pods/%s", id),
 		map[string]string{"fields": strings.Join(fields, ",")},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting device \n\t%v", err)
+		return nil, fmt.Errorf("failed getting device \n\t%w", err)
 	}
 
-	parsedResp := struct {
-		Status string
-		Result models.Device
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
-	}
-
-	return &parsedResp.Result, nil
+	return &result, nil
 }
 
 // GetDeviceACStates gets a device's AC stats by device ID.
@@ -80,27 +70,22 @@ func (s *Sensibo) GetDevice(ctx context.Context, id string, fields []string) (*m
 // id is the ID of the device
 // limit the amount of entries you get in the response
 func (s *Sensibo) GetDeviceACStates(ctx context.Context, id string, limit uint) ([]models.ACState, error) {
-	resp, err := s.makeGetRequest(
+	result := []models.ACState{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetDeviceACStates", id},
 		"v2",
 		fmt.Sprintf("pods/%s/acStates", id),
 		map[string]string{"limit": fmt.Sprintf("%d", limit)},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting AC State \n\t%v", err)
+		return nil, fmt.Errorf("failed getting AC State \n\t%w", err)
 	}
 
-	parsedResp := struct {
-		Status string
-		Result []models.ACState
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
-	}
-
-	return parsedResp.Result, nil
+	return result, nil
 }
 
 // GetDeviceHistoricalMeasurements gets historical measurements for a device.
@@ -108,131 +93,109 @@ func (s *Sensibo) GetDeviceACStates(ctx context.Context, id string, limit uint)
 // id is the ID of the device
 // days is the number of days we want to get the data for
 func (s *Sensibo) GetDeviceHistoricalMeasurements(ctx context.Context, id string, days uint) (*models.HistoricalMeasurements, error) {
-	resp, err := s.makeGetRequest(
+	result := models.HistoricalMeasurements{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetDeviceHistoricalMeasurements", id},
 		"v2",
 		fmt.Sprintf("pods/%s/historicalMeasurements", id),
 		map[string]string{"days": fmt.Sprintf("%d", days)},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting historical measurements \n\t%v", err)
+		return nil, fmt.Errorf("failed getting historical measurements \n\t%w", err)
 	}
 
-	parsedResp := struct {
-		Status string
-		Result models.HistoricalMeasurements
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
-	}
-
-	return &parsedResp.Result, nil
+	return &result, nil
 }
 
 // GetDeviceClimateReactSettings gets climate react settings for a device.
 //
 // id is the ID of the device
 func (s *Sensibo) GetDeviceClimateReactSettings(ctx context.Context, id string) (*models.ClimateReact, error) {
-	resp, err := s.makeGetRequest(
+	result := models.ClimateReact{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetDeviceClimateReactSettings", id},
 		"v2",
 		fmt.Sprintf("pods/%s/smartmode", id),
 		map[string]string{},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting climate react settings \n\t%v", err)
-	}
-
-	parsedResp := struct {
-		Status string
-		Result models.ClimateReact
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
+		return nil, fmt.Errorf("failed getting climate react settings \n\t%w", err)
 	}
 
-	return &parsedResp.Result, nil
+	return &result, nil
 }
 
 // GetDeviceTimer gets the timer for a device.
 //
 // id is the ID of the device
+//
+// It returns a nil timer and no error when no timer is set on the device
 func (s *Sensibo) GetDeviceTimer(ctx context.Context, id string) (*models.DeviceTimer, error) {
-	resp, err := s.makeGetRequest(
-		ctx,
-		"v1",
-		fmt.Sprintf("pods/%s/timer", id),
-		map[string]string{},
-	)
+	var result *models.DeviceTimer
+
+	// a device without a timer has a null result
+	err := s.makeRequest(ctx, request{
+		op:       operation{"GetDeviceTimer", id},
+		method:   http.MethodGet,
+		version:  "v1",
+		endpoint: fmt.Sprintf("pods/%s/timer", id),
+		params:   map[string]string{},
+		result:   &result,
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting timer \n\t%v", err)
-	}
-
-	parsedResp := struct {
-		Status string
-		Result models.DeviceTimer
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
+		return nil, fmt.Errorf("failed getting timer \n\t%w", err)
 	}
 
-	return &parsedResp.Result, nil
+	return result, nil
 }
 
 // GetDeviceSchedules gets all the schedules set on the device.
 //
 // id is the ID of the device
 func (s *Sensibo) GetDeviceSchedules(ctx context.Context, id string) ([]models.DeviceSchedule, error) {
-	resp, err := s.makeGetRequest(
+	result := []models.DeviceSchedule{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetDeviceSchedules", id},
 		"v1",
 		fmt.Sprintf("pods/%s/schedules", id),
 		map[string]string{},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting schedules \n\t%v", err)
-	}
-
-	parsedResp := struct {
-		Status string
-		Result []models.DeviceSchedule
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
+		return nil, fmt.Errorf("failed getting schedules \n\t%w", err)
 	}
 
-	return parsedResp.Result, nil
+	return result, nil
 }
 
 // GetDeviceSchedule gets a schedule by ID on the device.
 func (s *Sensibo) GetDeviceSchedule(ctx context.Context, deviceID string, scheduleID string) (*models.DeviceSchedule, error) {
-	resp, err := s.makeGetRequest(
+	result := models.DeviceSchedule{}
+
+	err := s.makeGetRequest(
 		ctx,
+		operation{"GetDeviceSchedule", deviceID},
 		"v1",
 		fmt.Sprintf("pods/%s/schedules/%s", deviceID, scheduleID),
 		map[string]string{},
+		&result,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed getting schedule \n\t%v", err)
-	}
-
-	parsedResp := struct {
-		Status string
-		Result models.DeviceSchedule
-	}{}
-
-	if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
-		return nil, fmt.Errorf("failed parsing result \n\t%v", err)
+		return nil, fmt.Errorf("failed getting schedule \n\t%w", err)
 	}
 
-	return &parsedResp.Result, nil
+	return &result, nil
 }
@@ -0,0 +1,252 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBodyMock(statusCode int, body string) *mocks.HTTPClientMock {
+	return &mocks.HTTPClientMock{
+		DoMock: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				StatusCode: statusCode,
+			}, nil
+		},
+	}
+}
+
+func newMockClient(doMock func(req *http.Request) (*http.Response, error), opts ...Option) *Sensibo {
+	return NewClient("api-key", append([]Option{
+		WithHTTPClient(&mocks.HTTPClientMock{DoMock: doMock}),
+	}, opts...)...)
+}
+
+func TestSensibo_GetAllDevices(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []models.Device
+		err  string
+	}{
+		{
+			name: "successful execution",
+			body: `{"status":"success","result":[{"id":"1234"}]}`,
+			want: []models.Device{{ID: "1234"}},
+			err:  "",
+		},
+		{
+			name: "returns an error when status is not success",
+			body: `{"status":"error","reason":"Unauthorized"}`,
+			want: nil,
+			err:  "failed getting all devices \n\tsensibo: response status \"error\": Unauthorized",
+		},
+		{
+			name: "returns an error when result is missing",
+			body: `{"status":"success"}`,
+			want: nil,
+			err:  "failed getting all devices \n\tsensibo: failed decoding response: sensibo: empty result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(newBodyMock(200, tt.body), "api-key")
+
+			got, err := s.GetAllDevices(context.Background(), []string{"*"})
+
+			assert.Equal(t, tt.want, got)
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSensibo_GetDevice(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *models.Device
+		err  string
+	}{
+		{
+			name: "successful execution",
+			body: `{"status":"success","result":{"id":"1234"}}`,
+			want: &models.Device{ID: "1234"},
+			err:  "",
+		},
+		{
+			name: "returns an error when result is null",
+			body: `{"status":"success","result":null}`,
+			want: nil,
+			err:  "failed getting device \n\tsensibo: failed decoding response: sensibo: empty result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(newBodyMock(200, tt.body), "api-key")
+
+			got, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+			assert.Equal(t, tt.want, got)
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSensibo_GetDeviceTimer(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *models.DeviceTimer
+		err  string
+	}{
+		{
+			name: "successful execution",
+			body: `{"status":"success","result":{"id":"1234","isEnabled":true}}`,
+			want: &models.DeviceTimer{ID: "1234", IsEnabled: true},
+			err:  "",
+		},
+		{
+			name: "returns no timer when result is null",
+			body: `{"status":"success","result":null}`,
+			want: nil,
+			err:  "",
+		},
+		{
+			name: "returns an error when status is not success",
+			body: `{"status":"error","reason":"NotFound"}`,
+			want: nil,
+			err:  "failed getting timer \n\tsensibo: response status \"error\": NotFound",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(newBodyMock(200, tt.body), "api-key")
+
+			got, err := s.GetDeviceTimer(context.Background(), "1234")
+
+			assert.Equal(t, tt.want, got)
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSensibo_GetDeviceSchedule(t *testing.T) {
+	s := New(newBodyMock(404, `{"status":"error","reason":"NotFound"}`), "api-key")
+
+	got, err := s.GetDeviceSchedule(context.Background(), "1234", "5678")
+
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "failed getting schedule \n\tsensibo: GET v1/pods/1234/schedules/5678: 404 Not Found: NotFound")
+}
+
+func benchmarkDevicesResponse(b *testing.B, count int) []byte {
+	devices := make([]models.Device, count)
+
+	for i := range devices {
+		devices[i].ID = fmt.Sprintf("device-%d", i)
+		devices[i].Room.Name = "Living Room"
+		devices[i].Tags = []string{"a", "b", "c"}
+		devices[i].Schedules = make([]models.DeviceSchedule, 5)
+		devices[i].RemoteCapabilities.Modes = map[models.ACMode]models.Mode{
+			models.ModeCool: {
+				FanLevels: []models.FanLevel{models.FanLow, models.FanMedium, models.FanHigh, models.FanAuto},
+				Swing:     []models.Swing{models.SwingStopped, models.SwingRangeFull},
+			},
+			models.ModeHeat: {
+				FanLevels: []models.FanLevel{models.FanLow, models.FanMedium, models.FanHigh, models.FanAuto},
+				Swing:     []models.Swing{models.SwingStopped, models.SwingRangeFull},
+			},
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"status": "success", "result": devices})
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return body
+}
+
+// BenchmarkSensibo_GetAllDevices decodes the response straight from the body.
+func BenchmarkSensibo_GetAllDevices(b *testing.B) {
+	body := benchmarkDevicesResponse(b, 300)
+	s := New(&mocks.HTTPClientMock{
+		DoMock: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+				StatusCode: 200,
+			}, nil
+		},
+	}, "api-key")
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GetAllDevices(context.Background(), []string{"*"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBufferedDecoding is the baseline for BenchmarkSensibo_GetAllDevices,
+// it reads the body into a string and converts it back for json.Unmarshal.
+func BenchmarkBufferedDecoding(b *testing.B) {
+	body := benchmarkDevicesResponse(b, 300)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		resBytes, err := ioutil.ReadAll(ioutil.NopCloser(bytes.NewReader(body)))
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		resp := string(resBytes)
+		parsedResp := struct {
+			Status string
+			Result []models.Device
+		}{}
+
+		if err := json.Unmarshal([]byte(resp), &parsedResp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes a single attempt of a call to the Sensibo API.
+type RequestInfo struct {
+	// Operation is the name of the Sensibo method, e.g: "GetDeviceACStates".
+	Operation string
+	// DeviceID is the ID of the device the call is about, empty if none.
+	DeviceID string
+	Method   string
+	Version  string
+	Endpoint string
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+}
+
+// RequestEvent describes the outcome of a single attempt.
+type RequestEvent struct {
+	RequestInfo
+	// StatusCode is zero when no response was received.
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// Handler sends a request to the Sensibo API.
+//
+// The request does not carry the API key, it is added after
+// the last middleware.
+type Handler func(req *http.Request, info RequestInfo) (*http.Response, error)
+
+// Middleware wraps a Handler to act on requests and responses.
+type Middleware func(next Handler) Handler
+
+// Observer is called after every attempt of every request.
+type Observer func(ctx context.Context, event RequestEvent)
+
+// WithMiddleware adds middleware around every attempt of every request.
+//
+// The first middleware is the outermost one.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(s *Sensibo) {
+		s.middleware = append(s.middleware, middleware...)
+	}
+}
+
+// WithObserver adds an observer that is called after every attempt.
+func WithObserver(observer Observer) Option {
+	return func(s *Sensibo) {
+		s.observers = append(s.observers, observer)
+	}
+}
+
+// Span is a minimal tracing span, adapt it to the tracing library in use.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans, adapt it to the tracing library in use.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracingMiddleware starts a span named "sensibo.<Operation>" around every attempt.
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request, info RequestInfo) (*http.Response, error) {
+			ctx, span := tracer.Start(req.Context(), fmt.Sprintf("sensibo.%s", info.Operation))
+			defer span.End()
+
+			span.SetAttribute("sensibo.operation", info.Operation)
+			span.SetAttribute("sensibo.device_id", info.DeviceID)
+			span.SetAttribute("sensibo.api_version", info.Version)
+			span.SetAttribute("sensibo.attempt", info.Attempt)
+			span.SetAttribute("http.method", info.Method)
+
+			res, err := next(req.WithContext(ctx), info)
+
+			if err != nil {
+				span.RecordError(err)
+
+				return res, err
+			}
+
+			span.SetAttribute("http.status_code", res.StatusCode)
+
+			if res.StatusCode != http.StatusOK {
+				span.RecordError(fmt.Errorf("unexpected status code %d", res.StatusCode))
+			}
+
+			return res, nil
+		}
+	}
+}
+
+// send passes req through the middleware chain to the http client.
+func (s *Sensibo) send(apiKey string, info RequestInfo, req *http.Request) (*http.Response, error) {
+	handler := func(req *http.Request, info RequestInfo) (*http.Response, error) {
+		res, err := s.httpClient.Do(s.authorize(req, apiKey))
+
+		return res, redactError(err, apiKey)
+	}
+
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		handler = s.middleware[i](handler)
+	}
+
+	return handler(req, info)
+}
+
+// authorize returns a copy of req that carries the API key, either in the
+// apiKey query parameter or in the Authorization header.
+func (s *Sensibo) authorize(req *http.Request, apiKey string) *http.Request {
+	req = req.Clone(req.Context())
+
+	if s.authHeader {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+		return req
+	}
+
+	query := fmt.Sprintf("apiKey=%s", apiKey)
+
+	if req.URL.RawQuery != "" {
+		query = fmt.Sprintf("%s&%s", query, req.URL.RawQuery)
+	}
+
+	req.URL.RawQuery = query
+
+	return req
+}
+
+func (s *Sensibo) observe(ctx context.Context, event RequestEvent) {
+	for _, observer := range s.observers {
+		observer(ctx, event)
+	}
+}
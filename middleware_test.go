@@ -0,0 +1,160 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type spanMock struct {
+	name  string
+	attrs map[string]interface{}
+	errs  []error
+	ended bool
+}
+
+func (s *spanMock) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *spanMock) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *spanMock) End()                                       { s.ended = true }
+
+type tracerMock struct {
+	spans []*spanMock
+}
+
+func (t *tracerMock) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &spanMock{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+
+	return ctx, span
+}
+
+func newStatusClient(statusCodes []int, opts ...Option) *Sensibo {
+	calls := 0
+
+	return newMockClient(func(req *http.Request) (*http.Response, error) {
+		code := statusCodes[calls]
+		calls++
+
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
+			StatusCode: code,
+		}, nil
+	}, opts...)
+}
+
+func TestWithMiddleware_order(t *testing.T) {
+	calls := []string{}
+	mw := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *http.Request, info RequestInfo) (*http.Response, error) {
+				calls = append(calls, fmt.Sprintf("%s:%s:%s", name, info.Operation, info.DeviceID))
+
+				return next(req, info)
+			}
+		}
+	}
+
+	s := newStatusClient([]int{200}, WithMiddleware(mw("a"), mw("b")))
+
+	err := s.DeleteDeviceSchedule(context.Background(), "1234", "5678")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a:DeleteDeviceSchedule:1234", "b:DeleteDeviceSchedule:1234"}, calls)
+}
+
+func TestWithMiddleware_apiKeyIsNotExposed(t *testing.T) {
+	seen := []string{}
+	events := []RequestEvent{}
+
+	s := NewClient(
+		"SECRETKEY123",
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return nil, fmt.Errorf("dial %s: connection refused", req.URL)
+			},
+		}),
+		WithMiddleware(func(next Handler) Handler {
+			return func(req *http.Request, info RequestInfo) (*http.Response, error) {
+				seen = append(seen, req.URL.String(), req.Header.Get("Authorization"))
+
+				res, err := next(req, info)
+
+				if err != nil {
+					return res, fmt.Errorf("request to %s failed: %w", req.URL, err)
+				}
+
+				return res, nil
+			}
+		}),
+		WithObserver(func(ctx context.Context, event RequestEvent) {
+			events = append(events, event)
+		}),
+	)
+
+	_, err := s.GetDeviceACStates(context.Background(), "1234", 5)
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "SECRETKEY123")
+	assert.Equal(t, []string{"https://home.sensibo.com/api/v2/pods/1234/acStates?limit=5", ""}, seen)
+	assert.Len(t, events, 1)
+	assert.NotContains(t, events[0].Err.Error(), "SECRETKEY123")
+}
+
+func TestWithObserver(t *testing.T) {
+	events := []RequestEvent{}
+
+	s := newStatusClient(
+		[]int{503, 200},
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+		WithObserver(func(ctx context.Context, event RequestEvent) {
+			events = append(events, event)
+		}),
+	)
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, RequestInfo{
+		Operation: "DeleteDeviceTimer",
+		DeviceID:  "1234",
+		Method:    http.MethodDelete,
+		Version:   "v1",
+		Endpoint:  "pods/1234/timer",
+		Attempt:   1,
+	}, events[0].RequestInfo)
+	assert.Equal(t, 503, events[0].StatusCode)
+	assert.Error(t, events[0].Err)
+	assert.Equal(t, 2, events[1].Attempt)
+	assert.Equal(t, 200, events[1].StatusCode)
+	assert.NoError(t, events[1].Err)
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	tracer := &tracerMock{}
+	s := newStatusClient([]int{404}, WithMiddleware(TracingMiddleware(tracer)))
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.Error(t, err)
+	assert.Len(t, tracer.spans, 1)
+
+	span := tracer.spans[0]
+
+	assert.Equal(t, "sensibo.DeleteDeviceTimer", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, "1234", span.attrs["sensibo.device_id"])
+	assert.Equal(t, 404, span.attrs["http.status_code"])
+	assert.Len(t, span.errs, 1)
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+// Call is a call recorded by ClientMock.
+type Call struct {
+	// Method is the name of the called method, e.g: "GetDevice".
+	Method string
+	// Args are the arguments of the call without the context.
+	Args []interface{}
+}
+
+// ClientMock implements sensibo.Client with a stub function per method.
+//
+// Every call is recorded, calling a method whose stub is not set returns
+// zero values and an error. It is safe for concurrent use.
+type ClientMock struct {
+	GetAllDevicesMock                   func(ctx context.Context, fields []string) ([]models.Device, error)
+	GetDeviceMock                       func(ctx context.Context, id string, fields []string) (*models.Device, error)
+	GetDeviceACStatesMock               func(ctx context.Context, id string, limit uint) ([]models.ACState, error)
+	GetDeviceHistoricalMeasurementsMock func(ctx context.Context, id string, days uint) (*models.HistoricalMeasurements, error)
+	GetDeviceClimateReactSettingsMock   func(ctx context.Context, id string) (*models.ClimateReact, error)
+	GetDeviceTimerMock                  func(ctx context.Context, id string) (*models.DeviceTimer, error)
+	GetDeviceSchedulesMock              func(ctx context.Context, id string) ([]models.DeviceSchedule, error)
+	GetDeviceScheduleMock               func(ctx context.Context, deviceID string, scheduleID string) (*models.DeviceSchedule, error)
+	SetDeviceACStatePropertyMock        func(ctx context.Context, id string, property string, value string) (*models.ACState, error)
+	SetDeviceACStateMock                func(ctx context.Context, id string, state models.ACStateData) (*models.ACState, error)
+	UpdateDeviceACStateMock             func(ctx context.Context, id string, patch models.ACStatePatch) (*models.ACState, error)
+	CreateDeviceScheduleMock            func(ctx context.Context, id string, schedule models.CreateDeviceSchedulePayload) (*models.DeviceSchedule, error)
+	SetDeviceTimerMock                  func(ctx context.Context, id string, minutesFromNow int, state models.ACStateData) (*models.DeviceTimer, error)
+	ToggleDeviceClimateReactMock        func(ctx context.Context, id string, enabled bool) (*models.ClimateReact, error)
+	ToggleDeviceScheduleMock            func(ctx context.Context, deviceID string, scheduleID string, enabled bool) (*models.DeviceSchedule, error)
+	DeleteDeviceTimerMock               func(ctx context.Context, id string) error
+	DeleteDeviceScheduleMock            func(ctx context.Context, deviceID string, scheduleID string) error
+	DoMock                              func(
+		ctx context.Context,
+		method string,
+		version string,
+		endpoint string,
+		query map[string]string,
+		body interface{},
+		out interface{},
+	) error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns all recorded calls in order.
+func (m *ClientMock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call{}, m.calls...)
+}
+
+// CallsTo returns the recorded calls of method in order.
+func (m *ClientMock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := []Call{}
+
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+
+	return calls
+}
+
+// Reset forgets all recorded calls.
+func (m *ClientMock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+func (m *ClientMock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func notStubbed(method string) error {
+	return fmt.Errorf("mocks: %s is not stubbed", method)
+}
+
+// GetAllDevices calls GetAllDevicesMock.
+func (m *ClientMock) GetAllDevices(ctx context.Context, fields []string) ([]models.Device, error) {
+	m.record("GetAllDevices", fields)
+
+	if m.GetAllDevicesMock == nil {
+		return nil, notStubbed("GetAllDevices")
+	}
+
+	return m.GetAllDevicesMock(ctx, fields)
+}
+
+// GetDevice calls GetDeviceMock.
+func (m *ClientMock) GetDevice(ctx context.Context, id string, fields []string) (*models.Device, error) {
+	m.record("GetDevice", id, fields)
+
+	if m.GetDeviceMock == nil {
+		return nil, notStubbed("GetDevice")
+	}
+
+	return m.GetDeviceMock(ctx, id, fields)
+}
+
+// GetDeviceACStates calls GetDeviceACStatesMock.
+func (m *ClientMock) GetDeviceACStates(ctx context.Context, id string, limit uint) ([]models.ACState, error) {
+	m.record("GetDeviceACStates", id, limit)
+
+	if m.GetDeviceACStatesMock == nil {
+		return nil, notStubbed("GetDeviceACStates")
+	}
+
+	return m.GetDeviceACStatesMock(ctx, id, limit)
+}
+
+// GetDeviceHistoricalMeasurements calls GetDeviceHistoricalMeasurementsMock.
+func (m *ClientMock) GetDeviceHistoricalMeasurements(
+	ctx context.Context,
+	id string,
+	days uint,
+) (*models.HistoricalMeasurements, error) {
+	m.record("GetDeviceHistoricalMeasurements", id, days)
+
+	if m.GetDeviceHistoricalMeasurementsMock == nil {
+		return nil, notStubbed("GetDeviceHistoricalMeasurements")
+	}
+
+	return m.GetDeviceHistoricalMeasurementsMock(ctx, id, days)
+}
+
+// GetDeviceClimateReactSettings calls GetDeviceClimateReactSettingsMock.
+func (m *ClientMock) GetDeviceClimateReactSettings(ctx context.Context, id string) (*models.ClimateReact, error) {
+	m.record("GetDeviceClimateReactSettings", id)
+
+	if m.GetDeviceClimateReactSettingsMock == nil {
+		return nil, notStubbed("GetDeviceClimateReactSettings")
+	}
+
+	return m.GetDeviceClimateReactSettingsMock(ctx, id)
+}
+
+// GetDeviceTimer calls GetDeviceTimerMock.
+func (m *ClientMock) GetDeviceTimer(ctx context.Context, id string) (*models.DeviceTimer, error) {
+	m.record("GetDeviceTimer", id)
+
+	if m.GetDeviceTimerMock == nil {
+		return nil, notStubbed("GetDeviceTimer")
+	}
+
+	return m.GetDeviceTimerMock(ctx, id)
+}
+
+// GetDeviceSchedules calls GetDeviceSchedulesMock.
+func (m *ClientMock) GetDeviceSchedules(ctx context.Context, id string) ([]models.DeviceSchedule, error) {
+	m.record("GetDeviceSchedules", id)
+
+	if m.GetDeviceSchedulesMock == nil {
+		return nil, notStubbed("GetDeviceSchedules")
+	}
+
+	return m.GetDeviceSchedulesMock(ctx, id)
+}
+
+// GetDeviceSchedule calls GetDeviceScheduleMock.
+func (m *ClientMock) GetDeviceSchedule(
+	ctx context.Context,
+	deviceID string,
+	scheduleID string,
+) (*models.DeviceSchedule, error) {
+	m.record("GetDeviceSchedule", deviceID, scheduleID)
+
+	if m.GetDeviceScheduleMock == nil {
+		return nil, notStubbed("GetDeviceSchedule")
+	}
+
+	return m.GetDeviceScheduleMock(ctx, deviceID, scheduleID)
+}
+
+// SetDeviceACStateProperty calls SetDeviceACStatePropertyMock.
+func (m *ClientMock) SetDeviceACStateProperty(
+	ctx context.Context,
+	id string,
+	property string,
+	value string,
+) (*models.ACState, error) {
+	m.record("SetDeviceACStateProperty", id, property, value)
+
+	if m.SetDeviceACStatePropertyMock == nil {
+		return nil, notStubbed("SetDeviceACStateProperty")
+	}
+
+	return m.SetDeviceACStatePropertyMock(ctx, id, property, value)
+}
+
+// SetDeviceACState calls SetDeviceACStateMock.
+func (m *ClientMock) SetDeviceACState(ctx context.Context, id string, state models.ACStateData) (*models.ACState, error) {
+	m.record("SetDeviceACState", id, state)
+
+	if m.SetDeviceACStateMock == nil {
+		return nil, notStubbed("SetDeviceACState")
+	}
+
+	return m.SetDeviceACStateMock(ctx, id, state)
+}
+
+// UpdateDeviceACState calls UpdateDeviceACStateMock.
+func (m *ClientMock) UpdateDeviceACState(ctx context.Context, id string, patch models.ACStatePatch) (*models.ACState, error) {
+	m.record("UpdateDeviceACState", id, patch)
+
+	if m.UpdateDeviceACStateMock == nil {
+		return nil, notStubbed("UpdateDeviceACState")
+	}
+
+	return m.UpdateDeviceACStateMock(ctx, id, patch)
+}
+
+// CreateDeviceSchedule calls CreateDeviceScheduleMock.
+func (m *ClientMock) CreateDeviceSchedule(
+	ctx context.Context,
+	id string,
+	schedule models.CreateDeviceSchedulePayload,
+) (*models.DeviceSchedule, error) {
+	m.record("CreateDeviceSchedule", id, schedule)
+
+	if m.CreateDeviceScheduleMock == nil {
+		return nil, notStubbed("CreateDeviceSchedule")
+	}
+
+	return m.CreateDeviceScheduleMock(ctx, id, schedule)
+}
+
+// SetDeviceTimer calls SetDeviceTimerMock.
+func (m *ClientMock) SetDeviceTimer(
+	ctx context.Context,
+	id string,
+	minutesFromNow int,
+	state models.ACStateData,
+) (*models.DeviceTimer, error) {
+	m.record("SetDeviceTimer", id, minutesFromNow, state)
+
+	if m.SetDeviceTimerMock == nil {
+		return nil, notStubbed("SetDeviceTimer")
+	}
+
+	return m.SetDeviceTimerMock(ctx, id, minutesFromNow, state)
+}
+
+// ToggleDeviceClimateReact calls ToggleDeviceClimateReactMock.
+func (m *ClientMock) ToggleDeviceClimateReact(ctx context.Context, id string, enabled bool) (*models.ClimateReact, error) {
+	m.record("ToggleDeviceClimateReact", id, enabled)
+
+	if m.ToggleDeviceClimateReactMock == nil {
+		return nil, notStubbed("ToggleDeviceClimateReact")
+	}
+
+	return m.ToggleDeviceClimateReactMock(ctx, id, enabled)
+}
+
+// ToggleDeviceSchedule calls ToggleDeviceScheduleMock.
+func (m *ClientMock) ToggleDeviceSchedule(
+	ctx context.Context,
+	deviceID string,
+	scheduleID string,
+	enabled bool,
+) (*models.DeviceSchedule, error) {
+	m.record("ToggleDeviceSchedule", deviceID, scheduleID, enabled)
+
+	if m.ToggleDeviceScheduleMock == nil {
+		return nil, notStubbed("ToggleDeviceSchedule")
+	}
+
+	return m.ToggleDeviceScheduleMock(ctx, deviceID, scheduleID, enabled)
+}
+
+// DeleteDeviceTimer calls DeleteDeviceTimerMock.
+func (m *ClientMock) DeleteDeviceTimer(ctx context.Context, id string) error {
+	m.record("DeleteDeviceTimer", id)
+
+	if m.DeleteDeviceTimerMock == nil {
+		return notStubbed("DeleteDeviceTimer")
+	}
+
+	return m.DeleteDeviceTimerMock(ctx, id)
+}
+
+// DeleteDeviceSchedule calls DeleteDeviceScheduleMock.
+func (m *ClientMock) DeleteDeviceSchedule(ctx context.Context, deviceID string, scheduleID string) error {
+	m.record("DeleteDeviceSchedule", deviceID, scheduleID)
+
+	if m.DeleteDeviceScheduleMock == nil {
+		return notStubbed("DeleteDeviceSchedule")
+	}
+
+	return m.DeleteDeviceScheduleMock(ctx, deviceID, scheduleID)
+}
+
+// Do calls DoMock.
+func (m *ClientMock) Do(
+	ctx context.Context,
+	method string,
+	version string,
+	endpoint string,
+	query map[string]string,
+	body interface{},
+	out interface{},
+) error {
+	m.record("Do", method, version, endpoint, query, body, out)
+
+	if m.DoMock == nil {
+		return notStubbed("Do")
+	}
+
+	return m.DoMock(ctx, method, version, endpoint, query, body, out)
+}
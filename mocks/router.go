@@ -0,0 +1,357 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mocks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TestingT is the part of *testing.T used by Router.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Router is a declarative HTTP client mock that answers requests from
+// registered expectations, use Client to get an HTTPClientMock.
+//
+// It is safe for concurrent use.
+type Router struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	unexpected   []string
+}
+
+// NewRouter creates a router without expectations.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Client returns an HTTPClientMock answering from the router.
+func (r *Router) Client() *HTTPClientMock {
+	return &HTTPClientMock{DoMock: r.Do}
+}
+
+// Expect registers an expectation for requests with method whose path
+// ends with pattern, e.g: "pods/{id}/schedules/{sid}" or "v2/pods/{id}".
+// Segments in braces match any non empty value.
+//
+// By default it expects exactly one request and answers with an empty
+// successful envelope.
+func (r *Router) Expect(method string, pattern string) *Expectation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := &Expectation{
+		method:     method,
+		pattern:    strings.Split(strings.Trim(pattern, "/"), "/"),
+		query:      map[string]string{},
+		times:      1,
+		statusCode: http.StatusOK,
+		body:       `{"status":"success","result":{}}`,
+	}
+	r.expectations = append(r.expectations, e)
+
+	return e
+}
+
+// Do answers req from the first matching expectation that has calls left.
+func (r *Router) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body = data
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.expectations {
+		if e.times > 0 && e.calls >= e.times {
+			continue
+		}
+
+		if !e.matches(req, body) {
+			continue
+		}
+
+		e.calls++
+
+		if e.err != nil {
+			return nil, e.err
+		}
+
+		return &http.Response{
+			StatusCode: e.statusCode,
+			Header:     e.header.Clone(),
+			Body:       ioutil.NopCloser(strings.NewReader(e.body)),
+			Request:    req,
+		}, nil
+	}
+
+	r.unexpected = append(r.unexpected, fmt.Sprintf("%s %s %s", req.Method, req.URL.Path, body))
+
+	return nil, fmt.Errorf("mocks: unexpected request %s %s", req.Method, req.URL.Path)
+}
+
+// Verify reports every unmet expectation and unexpected request to t,
+// it returns true if there were none.
+func (r *Router) Verify(t TestingT) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ok := true
+
+	for _, e := range r.expectations {
+		if (e.times > 0 && e.calls != e.times) || (e.times == 0 && e.calls == 0) {
+			t.Errorf("mocks: expected %s to be called %s, got %d calls", e, e.timesString(), e.calls)
+			ok = false
+		}
+	}
+
+	for _, u := range r.unexpected {
+		t.Errorf("mocks: unexpected request %s", u)
+		ok = false
+	}
+
+	return ok
+}
+
+// Expectation is a request expected by a Router and its canned response.
+type Expectation struct {
+	method  string
+	pattern []string
+	query   map[string]string
+	jsonSet bool
+	json    interface{}
+	times   int
+	calls   int
+
+	statusCode int
+	header     http.Header
+	body       string
+	err        error
+}
+
+// WithQuery requires the query parameter key to have value.
+func (e *Expectation) WithQuery(key string, value string) *Expectation {
+	e.query[key] = value
+
+	return e
+}
+
+// WithJSONBody requires the request body to contain the JSON value of v,
+// objects in the body may have more keys than the ones in v.
+func (e *Expectation) WithJSONBody(v interface{}) *Expectation {
+	e.jsonSet = true
+	e.json = normalizeJSON(v)
+
+	return e
+}
+
+// Times sets how many requests are expected, 0 allows any number but at least one.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+
+	return e
+}
+
+// ReturnResult answers with status 200 and a successful envelope holding result.
+func (e *Expectation) ReturnResult(result interface{}) *Expectation {
+	data, err := json.Marshal(map[string]interface{}{"status": "success", "result": result})
+
+	if err != nil {
+		panic(fmt.Sprintf("mocks: failed encoding result: %v", err))
+	}
+
+	return e.Return(http.StatusOK, string(data))
+}
+
+// ReturnError answers with statusCode and a Sensibo error body.
+func (e *Expectation) ReturnError(statusCode int, reason string, message string) *Expectation {
+	data, _ := json.Marshal(map[string]string{"status": "error", "reason": reason, "message": message})
+
+	return e.Return(statusCode, string(data))
+}
+
+// Return answers with statusCode and body.
+func (e *Expectation) Return(statusCode int, body string) *Expectation {
+	e.statusCode = statusCode
+	e.body = body
+
+	return e
+}
+
+// ReturnHeader adds a header to the response.
+func (e *Expectation) ReturnHeader(key string, value string) *Expectation {
+	if e.header == nil {
+		e.header = http.Header{}
+	}
+
+	e.header.Add(key, value)
+
+	return e
+}
+
+// ReturnTransportError fails the request with err instead of responding.
+func (e *Expectation) ReturnTransportError(err error) *Expectation {
+	e.err = err
+
+	return e
+}
+
+// String describes the expected request.
+func (e *Expectation) String() string {
+	str := fmt.Sprintf("%s %s", e.method, strings.Join(e.pattern, "/"))
+
+	if len(e.query) > 0 {
+		str = fmt.Sprintf("%s %v", str, e.query)
+	}
+
+	if e.jsonSet {
+		data, _ := json.Marshal(e.json)
+		str = fmt.Sprintf("%s %s", str, data)
+	}
+
+	return str
+}
+
+func (e *Expectation) timesString() string {
+	if e.times == 0 {
+		return "at least once"
+	}
+
+	return fmt.Sprintf("%d times", e.times)
+}
+
+func (e *Expectation) matches(req *http.Request, body []byte) bool {
+	if req.Method != e.method || !matchPathSuffix(e.pattern, req.URL.Path) {
+		return false
+	}
+
+	query := req.URL.Query()
+
+	for k, v := range e.query {
+		if query.Get(k) != v {
+			return false
+		}
+	}
+
+	if !e.jsonSet {
+		return true
+	}
+
+	var actual interface{}
+
+	if err := json.Unmarshal(body, &actual); err != nil {
+		return false
+	}
+
+	return containsJSON(actual, e.json)
+}
+
+// matchPathSuffix reports whether the last segments of path match pattern.
+func matchPathSuffix(pattern []string, path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(segments) < len(pattern) {
+		return false
+	}
+
+	segments = segments[len(segments)-len(pattern):]
+
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if segments[i] == "" {
+				return false
+			}
+
+			continue
+		}
+
+		if p != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeJSON converts v to the generic form produced by json.Unmarshal,
+// strings and byte slices are parsed as JSON documents.
+func normalizeJSON(v interface{}) interface{} {
+	var data []byte
+
+	switch b := v.(type) {
+	case string:
+		data = []byte(b)
+	case []byte:
+		data = b
+	default:
+		encoded, err := json.Marshal(v)
+
+		if err != nil {
+			panic(fmt.Sprintf("mocks: failed encoding JSON body: %v", err))
+		}
+
+		data = encoded
+	}
+
+	var normalized interface{}
+
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		panic(fmt.Sprintf("mocks: invalid JSON body: %v", err))
+	}
+
+	return normalized
+}
+
+// containsJSON reports whether actual holds expected, objects in actual
+// may have extra keys.
+func containsJSON(actual interface{}, expected interface{}) bool {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+
+		if !ok {
+			return false
+		}
+
+		for k, v := range exp {
+			if _, ok := act[k]; !ok || !containsJSON(act[k], v) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		act, ok := actual.([]interface{})
+
+		if !ok || len(act) != len(exp) {
+			return false
+		}
+
+		for i := range exp {
+			if !containsJSON(act[i], exp[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return reflect.DeepEqual(actual, expected)
+}
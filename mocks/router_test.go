@@ -0,0 +1,139 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func newRequest(method string, url string, body string) *http.Request {
+	req, _ := http.NewRequestWithContext(context.Background(), method, url, strings.NewReader(body))
+
+	return req
+}
+
+func readBody(t *testing.T, res *http.Response) string {
+	data, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter()
+	r.Expect(http.MethodPut, "pods/{id}/schedules/{sid}").
+		WithJSONBody(`{"isEnabled":true}`).
+		ReturnResult(map[string]string{"id": "s1"})
+	r.Expect(http.MethodGet, "v2/pods/{id}").
+		WithQuery("fields", "*").
+		Times(2).
+		ReturnError(http.StatusNotFound, "NotFound", "no such pod")
+
+	client := r.Client()
+
+	res, err := client.Do(newRequest(http.MethodPut, "https://host/api/v1/pods/1/schedules/s1?apiKey=k", `{"isEnabled":true,"extra":1}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `{"result":{"id":"s1"},"status":"success"}`, readBody(t, res))
+
+	for i := 0; i < 2; i++ {
+		res, err = client.Do(newRequest(http.MethodGet, "https://host/api/v2/pods/1?fields=*", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, `{"message":"no such pod","reason":"NotFound","status":"error"}`, readBody(t, res))
+	}
+
+	rt := &recordingT{}
+
+	assert.True(t, r.Verify(rt))
+	assert.Empty(t, rt.errors)
+}
+
+func TestRouter_mismatches(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"method", newRequest(http.MethodGet, "https://host/api/v1/pods/1/timer", `{"minutesFromNow":5}`)},
+		{"path", newRequest(http.MethodPut, "https://host/api/v1/pods/1/timers", `{"minutesFromNow":5}`)},
+		{"query", newRequest(http.MethodPut, "https://host/api/v1/pods/1/timer", `{"minutesFromNow":5}`)},
+		{"body value", newRequest(http.MethodPut, "https://host/api/v1/pods/1/timer?q=1", `{"minutesFromNow":6}`)},
+		{"body key", newRequest(http.MethodPut, "https://host/api/v1/pods/1/timer?q=1", `{"acState":{}}`)},
+		{"body not json", newRequest(http.MethodPut, "https://host/api/v1/pods/1/timer?q=1", `minutesFromNow`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.Expect(http.MethodPut, "pods/{id}/timer").
+				WithQuery("q", "1").
+				WithJSONBody(map[string]int{"minutesFromNow": 5})
+
+			_, err := r.Do(tt.req)
+
+			assert.EqualError(t, err, fmt.Sprintf("mocks: unexpected request %s %s", tt.req.Method, tt.req.URL.Path))
+
+			rt := &recordingT{}
+
+			assert.False(t, r.Verify(rt))
+			assert.Len(t, rt.errors, 2)
+			assert.Equal(t, `mocks: expected PUT pods/{id}/timer map[q:1] {"minutesFromNow":5} to be called 1 times, got 0 calls`, rt.errors[0])
+		})
+	}
+}
+
+func TestRouter_exhaustedExpectation(t *testing.T) {
+	r := NewRouter()
+	r.Expect(http.MethodDelete, "pods/{id}/timer")
+
+	_, err := r.Do(newRequest(http.MethodDelete, "https://host/api/v1/pods/1/timer", ""))
+	assert.NoError(t, err)
+
+	_, err = r.Do(newRequest(http.MethodDelete, "https://host/api/v1/pods/1/timer", ""))
+	assert.Error(t, err)
+
+	rt := &recordingT{}
+
+	assert.False(t, r.Verify(rt))
+	assert.Equal(t, []string{"mocks: unexpected request DELETE /api/v1/pods/1/timer "}, rt.errors)
+}
+
+func TestRouter_transportErrorAndAnyTimes(t *testing.T) {
+	transportErr := errors.New("connection reset")
+
+	r := NewRouter()
+	r.Expect(http.MethodGet, "pods/{id}/acStates").Times(0).ReturnTransportError(transportErr)
+
+	rt := &recordingT{}
+
+	assert.False(t, r.Verify(rt))
+	assert.Equal(t, []string{"mocks: expected GET pods/{id}/acStates to be called at least once, got 0 calls"}, rt.errors)
+
+	for i := 0; i < 3; i++ {
+		_, err := r.Do(newRequest(http.MethodGet, "https://host/api/v2/pods/1/acStates", ""))
+
+		assert.Equal(t, transportErr, err)
+	}
+
+	assert.True(t, r.Verify(&recordingT{}))
+}
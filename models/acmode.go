@@ -0,0 +1,32 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// ACMode is the operation mode of the AC.
+type ACMode string
+
+// AC modes.
+const (
+	ModeCool ACMode = "cool"
+	ModeHeat ACMode = "heat"
+	ModeFan  ACMode = "fan"
+	ModeDry  ACMode = "dry"
+	ModeAuto ACMode = "auto"
+)
+
+// Valid reports whether m is one of the known modes.
+func (m ACMode) Valid() bool {
+	switch m {
+	case ModeCool, ModeHeat, ModeFan, ModeDry, ModeAuto:
+		return true
+	}
+
+	return false
+}
+
+// String returns the API value of the mode.
+func (m ACMode) String() string {
+	return string(m)
+}
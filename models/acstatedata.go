@@ -4,12 +4,105 @@
 
 package models
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrUnknownProperty is returned by ACStateData.WithProperty for
+// properties that ACStateData has no field for.
+var ErrUnknownProperty = errors.New("unknown property")
+
 // ACStateData hold information about the state of the AC.
+//
+// TargetTemperature may be fractional (e.g: 22.5) for ACs that support
+// it, whole temperatures are encoded as integers (e.g: 22) in JSON.
+//
+// HorizontalSwing and Light are omitted from JSON when empty because
+// not every AC supports them. Use ACStatePatch to change only some
+// of the properties.
 type ACStateData struct {
-	On                bool   `json:"on"`
-	Mode              string `json:"mode"`
-	FanLevel          string `json:"fanLevel"`
-	TargetTemperature int    `json:"targetTemperature"`
-	TemperatureUnit   string `json:"temperatureUnit"`
-	Swing             string `json:"swing"`
+	On                bool            `json:"on"`
+	Mode              ACMode          `json:"mode"`
+	FanLevel          FanLevel        `json:"fanLevel"`
+	TargetTemperature float64         `json:"targetTemperature"`
+	TemperatureUnit   TemperatureUnit `json:"temperatureUnit"`
+	Swing             Swing           `json:"swing"`
+	HorizontalSwing   HorizontalSwing `json:"horizontalSwing,omitempty"`
+	Light             Light           `json:"light,omitempty"`
+}
+
+// WithProperty returns a copy of the state with property set to value,
+// property is the JSON name of a field, e.g: "targetTemperature".
+func (s ACStateData) WithProperty(property string, value string) (ACStateData, error) {
+	switch property {
+	case "on":
+		on, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return s, fmt.Errorf("invalid value %q for on", value)
+		}
+
+		s.On = on
+	case "mode":
+		s.Mode = ACMode(value)
+	case "fanLevel":
+		s.FanLevel = FanLevel(value)
+	case "targetTemperature":
+		temperature, err := strconv.ParseFloat(value, 64)
+
+		if err != nil || math.IsNaN(temperature) || math.IsInf(temperature, 0) {
+			return s, fmt.Errorf("invalid value %q for targetTemperature", value)
+		}
+
+		s.TargetTemperature = temperature
+	case "temperatureUnit":
+		s.TemperatureUnit = TemperatureUnit(value)
+	case "swing":
+		s.Swing = Swing(value)
+	case "horizontalSwing":
+		s.HorizontalSwing = HorizontalSwing(value)
+	case "light":
+		s.Light = Light(value)
+	default:
+		return s, fmt.Errorf("%w %q", ErrUnknownProperty, property)
+	}
+
+	return s, nil
+}
+
+// UnknownValues returns a violation for every set field whose value is not
+// one of the values known to this package, or nil if there are none.
+func (s ACStateData) UnknownValues() []Violation {
+	var violations []Violation
+
+	unknown := func(field string, value string, valid bool) {
+		if value != "" && !valid {
+			violations = append(violations, Violation{Field: field, Value: value, Unknown: true})
+		}
+	}
+
+	unknown("mode", s.Mode.String(), s.Mode.Valid())
+	unknown("fanLevel", s.FanLevel.String(), s.FanLevel.Valid())
+	unknown("temperatureUnit", s.TemperatureUnit.String(), s.TemperatureUnit.Valid())
+	unknown("swing", s.Swing.String(), s.Swing.Valid())
+	unknown("horizontalSwing", s.HorizontalSwing.String(), s.HorizontalSwing.Valid())
+	unknown("light", s.Light.String(), s.Light.Valid())
+
+	return violations
+}
+
+// NormalizeTemperature rounds temperature to a tenth of a degree, this drops
+// float noise from conversions such as 71.60000000000001.
+func NormalizeTemperature(temperature float64) float64 {
+	return math.Round(temperature*10) / 10
+}
+
+// FormatTemperature formats a temperature the way the API expects it, with
+// at most one decimal and without a decimal point for whole temperatures,
+// e.g: "22" and "22.5".
+func FormatTemperature(temperature float64) string {
+	return strconv.FormatFloat(NormalizeTemperature(temperature), 'f', -1, 64)
 }
@@ -0,0 +1,84 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACStateData_fractionalTemperature(t *testing.T) {
+	for _, tt := range []struct {
+		temperature float64
+		want        string
+	}{
+		{22, `{"on":false,"mode":"","fanLevel":"","targetTemperature":22,"temperatureUnit":"","swing":""}`},
+		{22.5, `{"on":false,"mode":"","fanLevel":"","targetTemperature":22.5,"temperatureUnit":"","swing":""}`},
+	} {
+		data, err := json.Marshal(ACStateData{TargetTemperature: tt.temperature})
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, string(data))
+
+		patch, err := json.Marshal(ACStatePatch{}.WithTargetTemperature(tt.temperature))
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"targetTemperature":`+FormatTemperature(tt.temperature)+`}`, string(patch))
+	}
+
+	assert.Equal(t, "22", FormatTemperature(22))
+	assert.Equal(t, "22.5", FormatTemperature(22.5))
+	assert.Equal(t, "-0.5", FormatTemperature(-0.5))
+	assert.Equal(t, "71.6", FormatTemperature(71.60000000000001))
+	assert.Equal(t, "22", FormatTemperature(21.99999999999))
+	assert.Equal(t, 71.6, NormalizeTemperature(71.60000000000001))
+}
+
+func TestACStateData_fractionalTemperatureInResponses(t *testing.T) {
+	timer := DeviceTimer{}
+	body := `{"acState":{"on":true,"targetTemperature":22.5}}`
+
+	assert.NoError(t, json.Unmarshal([]byte(body), &timer))
+	assert.Equal(t, 22.5, timer.ACState.TargetTemperature)
+
+	schedule := DeviceSchedule{}
+
+	assert.NoError(t, json.Unmarshal([]byte(body), &schedule))
+	assert.Equal(t, 22.5, schedule.ACState.TargetTemperature)
+
+	climateReact := ClimateReact{}
+	body = `{"lowTemperatureState":{"on":true,"targetTemperature":23.5},"highTemperatureThreshold":26.5}`
+
+	assert.NoError(t, json.Unmarshal([]byte(body), &climateReact))
+	assert.Equal(t, 23.5, climateReact.LowTemperatureState.TargetTemperature)
+	assert.Equal(t, 26.5, climateReact.HighTemperatureThreshold)
+}
+
+func TestACStateData_WithProperty(t *testing.T) {
+	state, err := ACStateData{}.WithProperty("horizontalSwing", "rangeFull")
+
+	assert.NoError(t, err)
+	assert.Equal(t, HorizontalSwingRangeFull, state.HorizontalSwing)
+
+	state, err = state.WithProperty("light", "on")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ACStateData{HorizontalSwing: HorizontalSwingRangeFull, Light: LightOn}, state)
+
+	state, err = state.WithProperty("targetTemperature", "22.5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 22.5, state.TargetTemperature)
+
+	_, err = state.WithProperty("targetTemperature", "NaN")
+
+	assert.EqualError(t, err, `invalid value "NaN" for targetTemperature`)
+
+	_, err = state.WithProperty("turbo", "on")
+
+	assert.EqualError(t, err, `unknown property "turbo"`)
+}
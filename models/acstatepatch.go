@@ -0,0 +1,159 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// ACStatePatch holds the properties of the AC state to change,
+// nil fields are left as they are and are not sent to the API.
+//
+// The With methods return a copy of the patch with a property set, e.g:
+//
+//	patch := models.ACStatePatch{}.WithOn(true).WithTargetTemperature(22)
+type ACStatePatch struct {
+	On                *bool            `json:"on,omitempty"`
+	Mode              *ACMode          `json:"mode,omitempty"`
+	FanLevel          *FanLevel        `json:"fanLevel,omitempty"`
+	TargetTemperature *float64         `json:"targetTemperature,omitempty"`
+	TemperatureUnit   *TemperatureUnit `json:"temperatureUnit,omitempty"`
+	Swing             *Swing           `json:"swing,omitempty"`
+	HorizontalSwing   *HorizontalSwing `json:"horizontalSwing,omitempty"`
+	Light             *Light           `json:"light,omitempty"`
+}
+
+// WithOn returns a copy of the patch that turns the AC on or off.
+func (p ACStatePatch) WithOn(on bool) ACStatePatch {
+	p.On = &on
+
+	return p
+}
+
+// WithMode returns a copy of the patch that sets the mode.
+func (p ACStatePatch) WithMode(mode ACMode) ACStatePatch {
+	p.Mode = &mode
+
+	return p
+}
+
+// WithFanLevel returns a copy of the patch that sets the fan level.
+func (p ACStatePatch) WithFanLevel(fanLevel FanLevel) ACStatePatch {
+	p.FanLevel = &fanLevel
+
+	return p
+}
+
+// WithTargetTemperature returns a copy of the patch that sets the target temperature.
+func (p ACStatePatch) WithTargetTemperature(temperature float64) ACStatePatch {
+	p.TargetTemperature = &temperature
+
+	return p
+}
+
+// WithTemperatureUnit returns a copy of the patch that sets the temperature unit.
+func (p ACStatePatch) WithTemperatureUnit(unit TemperatureUnit) ACStatePatch {
+	p.TemperatureUnit = &unit
+
+	return p
+}
+
+// WithSwing returns a copy of the patch that sets the vertical swing.
+func (p ACStatePatch) WithSwing(swing Swing) ACStatePatch {
+	p.Swing = &swing
+
+	return p
+}
+
+// WithHorizontalSwing returns a copy of the patch that sets the horizontal swing.
+func (p ACStatePatch) WithHorizontalSwing(swing HorizontalSwing) ACStatePatch {
+	p.HorizontalSwing = &swing
+
+	return p
+}
+
+// WithLight returns a copy of the patch that sets the light.
+func (p ACStatePatch) WithLight(light Light) ACStatePatch {
+	p.Light = &light
+
+	return p
+}
+
+// Properties returns the JSON names of the properties the patch sets.
+func (p ACStatePatch) Properties() []string {
+	properties := []string{}
+
+	if p.On != nil {
+		properties = append(properties, "on")
+	}
+
+	if p.Mode != nil {
+		properties = append(properties, "mode")
+	}
+
+	if p.FanLevel != nil {
+		properties = append(properties, "fanLevel")
+	}
+
+	if p.TargetTemperature != nil {
+		properties = append(properties, "targetTemperature")
+	}
+
+	if p.TemperatureUnit != nil {
+		properties = append(properties, "temperatureUnit")
+	}
+
+	if p.Swing != nil {
+		properties = append(properties, "swing")
+	}
+
+	if p.HorizontalSwing != nil {
+		properties = append(properties, "horizontalSwing")
+	}
+
+	if p.Light != nil {
+		properties = append(properties, "light")
+	}
+
+	return properties
+}
+
+// IsEmpty reports whether the patch sets no properties.
+func (p ACStatePatch) IsEmpty() bool {
+	return len(p.Properties()) == 0
+}
+
+// Apply returns a copy of state with the properties of the patch set.
+func (p ACStatePatch) Apply(state ACStateData) ACStateData {
+	if p.On != nil {
+		state.On = *p.On
+	}
+
+	if p.Mode != nil {
+		state.Mode = *p.Mode
+	}
+
+	if p.FanLevel != nil {
+		state.FanLevel = *p.FanLevel
+	}
+
+	if p.TargetTemperature != nil {
+		state.TargetTemperature = *p.TargetTemperature
+	}
+
+	if p.TemperatureUnit != nil {
+		state.TemperatureUnit = *p.TemperatureUnit
+	}
+
+	if p.Swing != nil {
+		state.Swing = *p.Swing
+	}
+
+	if p.HorizontalSwing != nil {
+		state.HorizontalSwing = *p.HorizontalSwing
+	}
+
+	if p.Light != nil {
+		state.Light = *p.Light
+	}
+
+	return state
+}
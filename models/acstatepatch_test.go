@@ -0,0 +1,56 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACStatePatch_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch ACStatePatch
+		want  string
+	}{
+		{"empty", ACStatePatch{}, `{}`},
+		{"zero values are sent", ACStatePatch{}.WithOn(false).WithTargetTemperature(0), `{"on":false,"targetTemperature":0}`},
+		{
+			"all properties",
+			ACStatePatch{}.WithOn(true).WithMode(ModeHeat).WithFanLevel(FanLow).WithTargetTemperature(24).
+				WithTemperatureUnit(UnitC).WithSwing(SwingStopped).WithHorizontalSwing(HorizontalSwingFixedCenter).WithLight(LightOff),
+			`{"on":true,"mode":"heat","fanLevel":"low","targetTemperature":24,"temperatureUnit":"C",` +
+				`"swing":"stopped","horizontalSwing":"fixedCenter","light":"off"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.patch)
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestACStatePatch_Apply(t *testing.T) {
+	state := ACStateData{On: true, Mode: ModeCool, FanLevel: FanAuto, TargetTemperature: 22, TemperatureUnit: UnitC}
+	patch := ACStatePatch{}.WithOn(false).WithLight(LightDim)
+
+	assert.Equal(t, []string{"on", "light"}, patch.Properties())
+	assert.False(t, patch.IsEmpty())
+	assert.True(t, ACStatePatch{}.IsEmpty())
+	assert.Equal(t, ACStateData{
+		Mode:              ModeCool,
+		FanLevel:          FanAuto,
+		TargetTemperature: 22,
+		TemperatureUnit:   UnitC,
+		Light:             LightDim,
+	}, patch.Apply(state))
+	assert.Equal(t, state, ACStatePatch{}.Apply(state))
+}
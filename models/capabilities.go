@@ -0,0 +1,301 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// RemoteCapabilities holds what the AC supports in each of its modes.
+type RemoteCapabilities struct {
+	Modes map[ACMode]Mode
+}
+
+// Violation describes a value of an AC state that the device does not
+// support or that is not known to this package.
+type Violation struct {
+	// Field is the JSON name of the field, e.g: "fanLevel".
+	Field string
+	Value string
+	// Supported lists the supported values, it is empty when
+	// the field is not supported at all.
+	Supported []string
+	// Unknown is set when Value is not one of the values known to this
+	// package, e.g: a typo like "coool".
+	Unknown bool
+}
+
+// String describes the violation.
+func (v Violation) String() string {
+	if v.Unknown && len(v.Supported) == 0 {
+		return fmt.Sprintf("%s %q is not a known value", v.Field, v.Value)
+	}
+
+	if len(v.Supported) == 0 {
+		return fmt.Sprintf("%s %q is not supported", v.Field, v.Value)
+	}
+
+	return fmt.Sprintf("%s %q is not supported (supported: %s)", v.Field, v.Value, strings.Join(v.Supported, ", "))
+}
+
+// Validate checks state against the capabilities and returns the
+// unsupported values, or nil if there are none.
+//
+// Empty fields and a zero target temperature are treated as not set.
+// Unknown values that the device supports are accepted. When the
+// capabilities are unknown only the unknown values are returned,
+// see ACStateData.UnknownValues.
+func (c RemoteCapabilities) Validate(state ACStateData) []Violation {
+	if len(c.Modes) == 0 {
+		return state.UnknownValues()
+	}
+
+	violations := c.validate(state)
+	unknown := map[string]bool{}
+
+	for _, v := range state.UnknownValues() {
+		unknown[v.Field] = true
+	}
+
+	for i := range violations {
+		violations[i].Unknown = unknown[violations[i].Field]
+	}
+
+	return violations
+}
+
+func (c RemoteCapabilities) validate(state ACStateData) []Violation {
+	if state.Mode == "" {
+		return nil
+	}
+
+	mode, ok := c.Modes[state.Mode]
+
+	if !ok {
+		return []Violation{{Field: "mode", Value: state.Mode.String(), Supported: c.modeNames()}}
+	}
+
+	var violations []Violation
+
+	if len(mode.Temperatures) > 0 && state.TemperatureUnit != "" {
+		temperatures, ok := mode.Temperatures[state.TemperatureUnit]
+
+		if !ok {
+			violations = append(violations, Violation{
+				Field:     "temperatureUnit",
+				Value:     state.TemperatureUnit.String(),
+				Supported: mode.unitNames(),
+			})
+		} else if state.TargetTemperature != 0 && len(temperatures.Values) > 0 &&
+			!containsFloat(temperatures.Values, state.TargetTemperature) {
+			violations = append(violations, Violation{
+				Field:     "targetTemperature",
+				Value:     FormatTemperature(state.TargetTemperature),
+				Supported: formatFloats(temperatures.Values),
+			})
+		}
+	}
+
+	if state.FanLevel != "" && !containsFanLevel(mode.FanLevels, state.FanLevel) {
+		supported := make([]string, 0, len(mode.FanLevels))
+
+		for _, f := range mode.FanLevels {
+			supported = append(supported, f.String())
+		}
+
+		violations = append(violations, Violation{Field: "fanLevel", Value: state.FanLevel.String(), Supported: supported})
+	}
+
+	if state.Swing != "" && !containsSwing(mode.Swing, state.Swing) {
+		supported := make([]string, 0, len(mode.Swing))
+
+		for _, s := range mode.Swing {
+			supported = append(supported, s.String())
+		}
+
+		violations = append(violations, Violation{Field: "swing", Value: state.Swing.String(), Supported: supported})
+	}
+
+	if state.HorizontalSwing != "" && !containsHorizontalSwing(mode.HorizontalSwing, state.HorizontalSwing) {
+		supported := make([]string, 0, len(mode.HorizontalSwing))
+
+		for _, s := range mode.HorizontalSwing {
+			supported = append(supported, s.String())
+		}
+
+		violations = append(violations, Violation{
+			Field:     "horizontalSwing",
+			Value:     state.HorizontalSwing.String(),
+			Supported: supported,
+		})
+	}
+
+	if state.Light != "" && !containsLight(mode.Light, state.Light) {
+		supported := make([]string, 0, len(mode.Light))
+
+		for _, l := range mode.Light {
+			supported = append(supported, l.String())
+		}
+
+		violations = append(violations, Violation{Field: "light", Value: state.Light.String(), Supported: supported})
+	}
+
+	return violations
+}
+
+// ValidateProperty checks the state that results from setting property
+// to value on current, see Validate and ACStateData.WithProperty.
+//
+// Properties unknown to ACStateData (e.g: from newer firmware) are not checked.
+func (c RemoteCapabilities) ValidateProperty(current ACStateData, property string, value string) []Violation {
+	state, err := current.WithProperty(property, value)
+
+	if errors.Is(err, ErrUnknownProperty) {
+		return nil
+	}
+
+	if err != nil {
+		return []Violation{{Field: property, Value: value}}
+	}
+
+	return c.Validate(state)
+}
+
+// SnapTemperature returns state with the target temperature set to the
+// closest temperature supported in its mode and unit. The state is
+// returned as is when there is nothing to snap to.
+func (c RemoteCapabilities) SnapTemperature(state ACStateData) ACStateData {
+	if state.TargetTemperature == 0 {
+		return state
+	}
+
+	values := c.Modes[state.Mode].Temperatures[state.TemperatureUnit].Values
+
+	if len(values) == 0 {
+		return state
+	}
+
+	target := state.TargetTemperature
+	closest := values[0]
+
+	for _, v := range values[1:] {
+		if math.Abs(v-target) < math.Abs(closest-target) {
+			closest = v
+		}
+	}
+
+	state.TargetTemperature = closest
+
+	return state
+}
+
+// RoundTemperature returns state with the target temperature rounded to
+// a whole degree when its mode and unit support only whole temperatures.
+// The state is returned as is when the supported temperatures are unknown.
+func (c RemoteCapabilities) RoundTemperature(state ACStateData) ACStateData {
+	values := c.Modes[state.Mode].Temperatures[state.TemperatureUnit].Values
+
+	if len(values) == 0 {
+		return state
+	}
+
+	for _, v := range values {
+		if v != math.Trunc(v) {
+			return state
+		}
+	}
+
+	state.TargetTemperature = math.Round(state.TargetTemperature)
+
+	return state
+}
+
+func (c RemoteCapabilities) modeNames() []string {
+	names := make([]string, 0, len(c.Modes))
+
+	for m := range c.Modes {
+		names = append(names, m.String())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (m Mode) unitNames() []string {
+	names := make([]string, 0, len(m.Temperatures))
+
+	for u := range m.Temperatures {
+		names = append(names, u.String())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func containsFloat(values []float64, value float64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFanLevel(levels []FanLevel, level FanLevel) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsSwing(swings []Swing, swing Swing) bool {
+	for _, s := range swings {
+		if s == swing {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsHorizontalSwing(swings []HorizontalSwing, swing HorizontalSwing) bool {
+	for _, s := range swings {
+		if s == swing {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsLight(lights []Light, light Light) bool {
+	for _, l := range lights {
+		if l == light {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatFloats(values []float64) []string {
+	formatted := make([]string, 0, len(values))
+
+	for _, v := range values {
+		formatted = append(formatted, FormatTemperature(v))
+	}
+
+	return formatted
+}
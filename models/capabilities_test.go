@@ -0,0 +1,187 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCapabilities() RemoteCapabilities {
+	return RemoteCapabilities{Modes: map[ACMode]Mode{
+		ModeCool: {
+			Temperatures: map[TemperatureUnit]Temperature{
+				UnitC: {IsNative: true, Values: []float64{16, 17, 18, 20, 22}},
+			},
+			FanLevels:       []FanLevel{FanLow, FanHigh, FanAuto},
+			Swing:           []Swing{SwingStopped, SwingRangeFull},
+			HorizontalSwing: []HorizontalSwing{HorizontalSwingStopped, HorizontalSwingFixedCenter},
+			Light:           []Light{LightOn, LightOff},
+		},
+		ModeFan: {
+			FanLevels: []FanLevel{FanLow},
+		},
+	}}
+}
+
+func TestRemoteCapabilities_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		caps  RemoteCapabilities
+		state ACStateData
+		want  []Violation
+	}{
+		{
+			name:  "supported",
+			caps:  testCapabilities(),
+			state: ACStateData{On: true, Mode: ModeCool, FanLevel: FanAuto, TargetTemperature: 22, TemperatureUnit: UnitC, Swing: SwingStopped},
+		},
+		{
+			name:  "unknown capabilities",
+			state: ACStateData{Mode: ModeCool, FanLevel: FanHigh},
+		},
+		{
+			name:  "unknown values with unknown capabilities",
+			state: ACStateData{Mode: "coool", FanLevel: FanHigh, Light: "bright"},
+			want: []Violation{
+				{Field: "mode", Value: "coool", Unknown: true},
+				{Field: "light", Value: "bright", Unknown: true},
+			},
+		},
+		{
+			name:  "unknown values supported by the device",
+			caps:  RemoteCapabilities{Modes: map[ACMode]Mode{"eco": {FanLevels: []FanLevel{"turbo"}}}},
+			state: ACStateData{Mode: "eco", FanLevel: "turbo"},
+		},
+		{
+			name:  "unknown unsupported value",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeCool, FanLevel: "turbo"},
+			want:  []Violation{{Field: "fanLevel", Value: "turbo", Supported: []string{"low", "high", "auto"}, Unknown: true}},
+		},
+		{
+			name:  "empty fields are not checked",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeCool},
+		},
+		{
+			name:  "unsupported mode",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeHeat, FanLevel: "turbo"},
+			want:  []Violation{{Field: "mode", Value: "heat", Supported: []string{"cool", "fan"}}},
+		},
+		{
+			name:  "unsupported values",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeCool, FanLevel: FanMedium, TargetTemperature: 19, TemperatureUnit: UnitC, Swing: SwingBoth},
+			want: []Violation{
+				{Field: "targetTemperature", Value: "19", Supported: []string{"16", "17", "18", "20", "22"}},
+				{Field: "fanLevel", Value: "medium", Supported: []string{"low", "high", "auto"}},
+				{Field: "swing", Value: "both", Supported: []string{"stopped", "rangeFull"}},
+			},
+		},
+		{
+			name:  "fractional temperature",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeCool, TargetTemperature: 20.5, TemperatureUnit: UnitC},
+			want:  []Violation{{Field: "targetTemperature", Value: "20.5", Supported: []string{"16", "17", "18", "20", "22"}}},
+		},
+		{
+			name:  "unsupported unit",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeCool, TargetTemperature: 70, TemperatureUnit: UnitF},
+			want:  []Violation{{Field: "temperatureUnit", Value: "F", Supported: []string{"C"}}},
+		},
+		{
+			name:  "mode without swing",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeFan, Swing: SwingStopped},
+			want:  []Violation{{Field: "swing", Value: "stopped", Supported: []string{}}},
+		},
+		{
+			name:  "horizontal swing and light",
+			caps:  testCapabilities(),
+			state: ACStateData{Mode: ModeCool, HorizontalSwing: HorizontalSwingRangeFull, Light: LightDim},
+			want: []Violation{
+				{Field: "horizontalSwing", Value: "rangeFull", Supported: []string{"stopped", "fixedCenter"}},
+				{Field: "light", Value: "dim", Supported: []string{"on", "off"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.caps.Validate(tt.state))
+		})
+	}
+}
+
+func TestRemoteCapabilities_ValidateProperty(t *testing.T) {
+	current := ACStateData{On: true, Mode: ModeCool, FanLevel: FanHigh, TargetTemperature: 22, TemperatureUnit: UnitC}
+	caps := testCapabilities()
+
+	assert.Nil(t, caps.ValidateProperty(current, "fanLevel", "low"))
+	assert.Nil(t, caps.ValidateProperty(current, "on", "false"))
+	assert.Equal(t, []Violation{{Field: "fanLevel", Value: "high", Supported: []string{"low"}}},
+		caps.ValidateProperty(current, "mode", "fan"))
+	assert.Equal(t, []Violation{{Field: "targetTemperature", Value: "hot"}},
+		caps.ValidateProperty(current, "targetTemperature", "hot"))
+	assert.Nil(t, caps.ValidateProperty(current, "light", "on"))
+	assert.Equal(t, []Violation{{Field: "light", Value: "on", Supported: []string{}}},
+		caps.ValidateProperty(ACStateData{Mode: ModeFan}, "light", "on"))
+	assert.Nil(t, caps.ValidateProperty(current, "turbo", "on"))
+}
+
+func TestRemoteCapabilities_SnapTemperature(t *testing.T) {
+	caps := testCapabilities()
+	state := ACStateData{Mode: ModeCool, TemperatureUnit: UnitC}
+
+	for target, want := range map[float64]float64{19: 18, 21: 20, 30: 22, 10: 16, 0: 0, 20.4: 20, 21.5: 22} {
+		state.TargetTemperature = target
+
+		assert.Equal(t, want, caps.SnapTemperature(state).TargetTemperature, "target %v", target)
+	}
+
+	state.Mode = ModeFan
+	state.TargetTemperature = 19
+
+	assert.Equal(t, 19.0, caps.SnapTemperature(state).TargetTemperature)
+
+	halves := RemoteCapabilities{Modes: map[ACMode]Mode{
+		ModeHeat: {Temperatures: map[TemperatureUnit]Temperature{UnitC: {Values: []float64{20, 20.5, 21}}}},
+	}}
+	state = ACStateData{Mode: ModeHeat, TemperatureUnit: UnitC, TargetTemperature: 20.7}
+
+	assert.Equal(t, 20.5, halves.SnapTemperature(state).TargetTemperature)
+	assert.Nil(t, halves.Validate(halves.SnapTemperature(state)))
+}
+
+func TestRemoteCapabilities_RoundTemperature(t *testing.T) {
+	halves := RemoteCapabilities{Modes: map[ACMode]Mode{
+		ModeHeat: {Temperatures: map[TemperatureUnit]Temperature{UnitC: {Values: []float64{20, 20.5, 21}}}},
+	}}
+
+	for _, tt := range []struct {
+		caps  RemoteCapabilities
+		state ACStateData
+		want  float64
+	}{
+		{testCapabilities(), ACStateData{Mode: ModeCool, TemperatureUnit: UnitC, TargetTemperature: 20.5}, 21},
+		{testCapabilities(), ACStateData{Mode: ModeCool, TemperatureUnit: UnitC, TargetTemperature: 20.4}, 20},
+		{halves, ACStateData{Mode: ModeHeat, TemperatureUnit: UnitC, TargetTemperature: 20.5}, 20.5},
+		{testCapabilities(), ACStateData{Mode: ModeFan, TemperatureUnit: UnitC, TargetTemperature: 20.5}, 20.5},
+		{RemoteCapabilities{}, ACStateData{TargetTemperature: 20.5}, 20.5},
+	} {
+		assert.Equal(t, tt.want, tt.caps.RoundTemperature(tt.state).TargetTemperature, "%+v", tt.state)
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	assert.Equal(t, `swing "both" is not supported (supported: stopped, rangeFull)`,
+		Violation{Field: "swing", Value: "both", Supported: []string{"stopped", "rangeFull"}}.String())
+	assert.Equal(t, `light "on" is not supported`, Violation{Field: "light", Value: "on"}.String())
+	assert.Equal(t, `mode "coool" is not a known value`, Violation{Field: "mode", Value: "coool", Unknown: true}.String())
+}
@@ -0,0 +1,13 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// CreateDeviceSchedulePayload is the payload for the CreateDeviceSchedule API
+type CreateDeviceSchedulePayload struct {
+	TargetTimeLocal string      `json:"targetTimeLocal"`
+	TimeZone        string      `json:"timezone"`
+	ACState         ACStateData `json:"acState"`
+	RecurringDays   []string    `json:"recurOnDaysOfWeek"`
+}
@@ -12,7 +12,7 @@ type Device struct {
 	IsOwner                                         bool
 	ID                                              string
 	QRID                                            string
-	TemperatureUnit                                 string
+	TemperatureUnit                                 TemperatureUnit
 	Room                                            Room
 	ACState                                         struct {
 		Timestamp SensiboTime
@@ -32,7 +32,7 @@ type Device struct {
 	}
 	ConnectionStatus struct {
 		IsAlive  bool
-		lastSeen SensiboTime
+		LastSeen SensiboTime
 	}
 	FirmwareVersion                      string
 	FirmwareType                         string
@@ -56,7 +56,7 @@ type Device struct {
 	FiltersCleaning struct {
 		ACOnSecondsSinceLastFiltersClean float64
 		FiltersCleanSecondsThreshold     float64
-		LastFiltersCleanTime             string
+		LastFiltersCleanTime             Timestamp
 		ShouldCleanFilters               bool
 	}
 	RoomIsOccupied         string
@@ -66,10 +66,8 @@ type Device struct {
 	Features               []string
 	RunningHealthcheck     string
 	HomekitSupported       bool
-	RemoteCapabilities     struct {
-		Modes map[string]Mode
-	}
-	Remote struct {
+	RemoteCapabilities     RemoteCapabilities
+	Remote                 struct {
 		Toggle bool
 		Window bool
 	}
@@ -101,19 +99,13 @@ type Room struct {
 	Icon string
 }
 
-// SensiboTime holds general time information data structure that is re-usable.
-type SensiboTime struct {
-	Time       string
-	SecondsAgo float64
-}
-
 // Mode of the AC unit.
 type Mode struct {
-	Temperatures    map[string]Temperature
-	FanLevels       []string
-	Swing           []string
-	HorizontalSwing []string
-	Light           []string
+	Temperatures    map[TemperatureUnit]Temperature
+	FanLevels       []FanLevel
+	Swing           []Swing
+	HorizontalSwing []HorizontalSwing
+	Light           []Light
 }
 
 // Temperature histogram.
@@ -125,8 +117,6 @@ type Temperature struct {
 // ClimateReactState holds extended AC data for climate react.
 type ClimateReactState struct {
 	ACStateData
-	HorizontalSwing string
-	Light           string
 }
 
 // ClimateReact holds climate react data.
@@ -19,16 +19,14 @@ type DeviceSchedule struct {
 				PureBoost            string       `json:"pure_boost"`
 			}
 		}
-		HorizontalSwing string
-		Light           string
 	}
 	CausedBy               CausedBy
-	CreateTime             string
+	CreateTime             Timestamp
 	CreateTimeSecondsAgo   int
 	RecurringDays          []string
 	TargetTimeLocal        string
 	TimeZone               string
 	PodUID                 string
-	NextTime               string
+	NextTime               Timestamp
 	NextTimeSecondsFromNow int
 }
@@ -10,16 +10,16 @@ type DeviceTimer struct {
 	IsEnabled              bool
 	ACState                ACStateData
 	CausedBy               CausedBy
-	CreateTime             string
+	CreateTime             Timestamp
 	CreateTimeSecondsAgo   int
 	LastScheduledInstances []struct {
 		Type                 string
-		TargetTime           string
+		TargetTime           Timestamp
 		TargetTimeSecondsAgo int
 		Status               string
 		ScheduleID           string
 		LastExecutions       []string
 	}
-	TargetTime               string
+	TargetTime               Timestamp
 	TargetTimeSecondsFromNow int
 }
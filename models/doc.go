@@ -4,4 +4,10 @@
 
 // Package models holds all of the custom data types.
 // These types needed to communicate with Sensibo's API
+//
+// Settings of the AC such as ACMode and FanLevel are typed strings with
+// constants for the known values. Values not listed (e.g: from newer
+// firmware) are kept as is when decoding and encoding JSON, use Valid
+// to check for known values and RemoteCapabilities.Validate to check
+// a state before sending it.
 package models
@@ -0,0 +1,78 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnums_Valid(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+		value interface{ Valid() bool }
+	}{
+		{"mode", true, ModeCool},
+		{"unknown mode", false, ACMode("coool")},
+		{"fan level", true, FanMediumHigh},
+		{"unknown fan level", false, FanLevel("turbo")},
+		{"swing", true, SwingRangeFull},
+		{"unknown swing", false, Swing("diagonal")},
+		{"unit", true, UnitF},
+		{"unknown unit", false, TemperatureUnit("K")},
+		{"horizontal swing", true, HorizontalSwingFixedCenter},
+		{"unknown horizontal swing", false, HorizontalSwing("diagonal")},
+		{"light", true, LightDim},
+		{"unknown light", false, Light("bright")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.value.Valid())
+		})
+	}
+}
+
+func TestEnums_String(t *testing.T) {
+	assert.Equal(t, "dry", ModeDry.String())
+	assert.Equal(t, "medium_low", FanMediumLow.String())
+	assert.Equal(t, "fixedMiddleTop", SwingFixedMiddleTop.String())
+	assert.Equal(t, "C", UnitC.String())
+	assert.Equal(t, "rangeFull", HorizontalSwingRangeFull.String())
+	assert.Equal(t, "off", LightOff.String())
+}
+
+func TestACStateData_JSON(t *testing.T) {
+	body := `{"on":true,"mode":"eco","fanLevel":"turbo","targetTemperature":22,"temperatureUnit":"C","swing":"stopped"}`
+	state := ACStateData{}
+
+	assert.NoError(t, json.Unmarshal([]byte(body), &state))
+	assert.Equal(t, ACStateData{
+		On:                true,
+		Mode:              "eco",
+		FanLevel:          "turbo",
+		TargetTemperature: 22,
+		TemperatureUnit:   UnitC,
+		Swing:             SwingStopped,
+	}, state)
+	assert.False(t, state.Mode.Valid())
+
+	encoded, err := json.Marshal(state)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, body, string(encoded))
+}
+
+func TestMode_JSON(t *testing.T) {
+	body := `{"cool":{"temperatures":{"C":{"isNative":true,"values":[18,19]}},"fanLevels":["low","auto"],"swing":["stopped"]}}`
+	modes := map[ACMode]Mode{}
+
+	assert.NoError(t, json.Unmarshal([]byte(body), &modes))
+	assert.Equal(t, []FanLevel{FanLow, FanAuto}, modes[ModeCool].FanLevels)
+	assert.Equal(t, []float64{18, 19}, modes[ModeCool].Temperatures[UnitC].Values)
+}
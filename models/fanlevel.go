@@ -0,0 +1,35 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// FanLevel is the fan speed of the AC.
+type FanLevel string
+
+// Fan levels.
+const (
+	FanQuiet      FanLevel = "quiet"
+	FanLow        FanLevel = "low"
+	FanMediumLow  FanLevel = "medium_low"
+	FanMedium     FanLevel = "medium"
+	FanMediumHigh FanLevel = "medium_high"
+	FanHigh       FanLevel = "high"
+	FanStrong     FanLevel = "strong"
+	FanAuto       FanLevel = "auto"
+)
+
+// Valid reports whether f is one of the known fan levels.
+func (f FanLevel) Valid() bool {
+	switch f {
+	case FanQuiet, FanLow, FanMediumLow, FanMedium, FanMediumHigh, FanHigh, FanStrong, FanAuto:
+		return true
+	}
+
+	return false
+}
+
+// String returns the API value of the fan level.
+func (f FanLevel) String() string {
+	return string(f)
+}
@@ -6,12 +6,12 @@ package models
 
 // HistoricalMeasurements holds information about historical measurements.
 type HistoricalMeasurements struct {
-	Temperature []struct {
-		Time  string
-		Value float64
-	}
-	Humidity []struct {
-		Time  string
-		Value float64
-	}
+	Temperature []Measurement
+	Humidity    []Measurement
+}
+
+// Measurement is a single historical measurement.
+type Measurement struct {
+	Time  Timestamp
+	Value float64
 }
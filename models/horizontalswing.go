@@ -0,0 +1,44 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// HorizontalSwing is the horizontal swing setting of the AC.
+type HorizontalSwing string
+
+// Horizontal swing settings.
+const (
+	HorizontalSwingStopped          HorizontalSwing = "stopped"
+	HorizontalSwingFixedLeft        HorizontalSwing = "fixedLeft"
+	HorizontalSwingFixedCenterLeft  HorizontalSwing = "fixedCenterLeft"
+	HorizontalSwingFixedCenter      HorizontalSwing = "fixedCenter"
+	HorizontalSwingFixedCenterRight HorizontalSwing = "fixedCenterRight"
+	HorizontalSwingFixedRight       HorizontalSwing = "fixedRight"
+	HorizontalSwingFixedLeftRight   HorizontalSwing = "fixedLeftRight"
+	HorizontalSwingRangeCenter      HorizontalSwing = "rangeCenter"
+	HorizontalSwingRangeFull        HorizontalSwing = "rangeFull"
+)
+
+// Valid reports whether s is one of the known horizontal swing settings.
+func (s HorizontalSwing) Valid() bool {
+	switch s {
+	case HorizontalSwingStopped,
+		HorizontalSwingFixedLeft,
+		HorizontalSwingFixedCenterLeft,
+		HorizontalSwingFixedCenter,
+		HorizontalSwingFixedCenterRight,
+		HorizontalSwingFixedRight,
+		HorizontalSwingFixedLeftRight,
+		HorizontalSwingRangeCenter,
+		HorizontalSwingRangeFull:
+		return true
+	}
+
+	return false
+}
+
+// String returns the API value of the horizontal swing setting.
+func (s HorizontalSwing) String() string {
+	return string(s)
+}
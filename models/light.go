@@ -0,0 +1,30 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// Light is the setting of the display light of the AC.
+type Light string
+
+// Light settings.
+const (
+	LightOn  Light = "on"
+	LightOff Light = "off"
+	LightDim Light = "dim"
+)
+
+// Valid reports whether l is one of the known light settings.
+func (l Light) Valid() bool {
+	switch l {
+	case LightOn, LightOff, LightDim:
+		return true
+	}
+
+	return false
+}
+
+// String returns the API value of the light setting.
+func (l Light) String() string {
+	return string(l)
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// Swing is the vertical swing setting of the AC.
+type Swing string
+
+// Swing settings.
+const (
+	SwingStopped           Swing = "stopped"
+	SwingFixedTop          Swing = "fixedTop"
+	SwingFixedMiddleTop    Swing = "fixedMiddleTop"
+	SwingFixedMiddle       Swing = "fixedMiddle"
+	SwingFixedMiddleBottom Swing = "fixedMiddleBottom"
+	SwingFixedBottom       Swing = "fixedBottom"
+	SwingRangeTop          Swing = "rangeTop"
+	SwingRangeMiddle       Swing = "rangeMiddle"
+	SwingRangeBottom       Swing = "rangeBottom"
+	SwingRangeFull         Swing = "rangeFull"
+	SwingHorizontal        Swing = "horizontal"
+	SwingBoth              Swing = "both"
+)
+
+// Valid reports whether s is one of the known swing settings.
+func (s Swing) Valid() bool {
+	switch s {
+	case SwingStopped,
+		SwingFixedTop,
+		SwingFixedMiddleTop,
+		SwingFixedMiddle,
+		SwingFixedMiddleBottom,
+		SwingFixedBottom,
+		SwingRangeTop,
+		SwingRangeMiddle,
+		SwingRangeBottom,
+		SwingRangeFull,
+		SwingHorizontal,
+		SwingBoth:
+		return true
+	}
+
+	return false
+}
+
+// String returns the API value of the swing setting.
+func (s Swing) String() string {
+	return string(s)
+}
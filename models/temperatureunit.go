@@ -0,0 +1,24 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+// TemperatureUnit is the unit of a temperature.
+type TemperatureUnit string
+
+// Temperature units.
+const (
+	UnitC TemperatureUnit = "C"
+	UnitF TemperatureUnit = "F"
+)
+
+// Valid reports whether u is one of the known units.
+func (u TemperatureUnit) Valid() bool {
+	return u == UnitC || u == UnitF
+}
+
+// String returns the API value of the unit.
+func (u TemperatureUnit) String() string {
+	return string(u)
+}
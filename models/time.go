@@ -0,0 +1,118 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// timeLayouts are the layouts of the time strings sent by the API,
+// times without a zone are in UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// Timestamp is a time sent by the API.
+//
+// The time is always in UTC, Raw keeps the original string which is also
+// what is encoded back to JSON. A Timestamp that could not be parsed has
+// a zero time and its Raw value.
+type Timestamp struct {
+	time.Time
+	Raw string
+}
+
+// NewTimestamp returns the Timestamp of t.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t.UTC()}
+}
+
+// ParseTimestamp parses a time string of the API.
+func ParseTimestamp(raw string) Timestamp {
+	ts := Timestamp{Raw: raw}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			ts.Time = t.UTC()
+
+			break
+		}
+	}
+
+	return ts
+}
+
+// String returns Raw, or the time in RFC 3339 format if Raw is empty.
+func (t Timestamp) String() string {
+	if t.Raw != "" || t.Time.IsZero() {
+		return t.Raw
+	}
+
+	return t.Time.UTC().Format(time.RFC3339Nano)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts a string
+// or an object with a "time" string.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	raw := ""
+
+	if len(data) > 0 && data[0] == '{' {
+		obj := struct {
+			Time string `json:"time"`
+		}{}
+
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+
+		raw = obj.Time
+	} else if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = ParseTimestamp(raw)
+
+	return nil
+}
+
+// SensiboTime holds general time information data structure that is re-usable.
+//
+// SecondsAgo is computed by the API when it responds, which makes it a
+// reference that does not depend on the local clock, see Age and ServerTime.
+type SensiboTime struct {
+	Time       Timestamp
+	SecondsAgo float64
+}
+
+// Age returns how old the time was according to the server clock
+// when the response was sent.
+func (t SensiboTime) Age() time.Duration {
+	return time.Duration(t.SecondsAgo * float64(time.Second))
+}
+
+// ServerTime returns the time of the server clock when the response was
+// sent, it is zero if Time is unknown.
+//
+// Compare times to it rather than to time.Now to avoid clock skew.
+func (t SensiboTime) ServerTime() time.Time {
+	if t.Time.IsZero() {
+		return time.Time{}
+	}
+
+	return t.Time.Add(t.Age())
+}
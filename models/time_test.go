@@ -0,0 +1,113 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want time.Time
+		raw  string
+		err  string
+	}{
+		{
+			name: "RFC 3339",
+			json: `"2021-06-20T11:17:25.467803Z"`,
+			want: time.Date(2021, 6, 20, 11, 17, 25, 467803000, time.UTC),
+			raw:  "2021-06-20T11:17:25.467803Z",
+		},
+		{
+			name: "offset is converted to UTC",
+			json: `"2021-06-20T13:17:25+02:00"`,
+			want: time.Date(2021, 6, 20, 11, 17, 25, 0, time.UTC),
+			raw:  "2021-06-20T13:17:25+02:00",
+		},
+		{
+			name: "no zone is UTC",
+			json: `"2021-06-20T11:17:25"`,
+			want: time.Date(2021, 6, 20, 11, 17, 25, 0, time.UTC),
+			raw:  "2021-06-20T11:17:25",
+		},
+		{
+			name: "object with time",
+			json: `{"time":"2021-06-20 11:17:25","secondsAgo":5}`,
+			want: time.Date(2021, 6, 20, 11, 17, 25, 0, time.UTC),
+			raw:  "2021-06-20 11:17:25",
+		},
+		{
+			name: "invalid time keeps raw",
+			json: `"yesterday"`,
+			raw:  "yesterday",
+		},
+		{
+			name: "null",
+			json: `null`,
+		},
+		{
+			name: "wrong type",
+			json: `12`,
+			err:  "json: cannot unmarshal number into Go value of type string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := Timestamp{}
+			err := json.Unmarshal([]byte(tt.json), &ts)
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+
+			assert.Equal(t, tt.want, ts.Time)
+			assert.Equal(t, tt.raw, ts.Raw)
+		})
+	}
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		ts   Timestamp
+		want string
+	}{
+		{ParseTimestamp("2021-06-20T13:17:25+02:00"), `"2021-06-20T13:17:25+02:00"`},
+		{NewTimestamp(time.Date(2021, 6, 20, 13, 17, 25, 0, time.FixedZone("", 7200))), `"2021-06-20T11:17:25Z"`},
+		{Timestamp{}, `""`},
+	} {
+		data, err := json.Marshal(tt.ts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, string(data))
+	}
+}
+
+func TestSensiboTime(t *testing.T) {
+	st := SensiboTime{}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"time":"2021-06-20T11:17:25Z","secondsAgo":90.5}`), &st))
+	assert.Equal(t, time.Date(2021, 6, 20, 11, 17, 25, 0, time.UTC), st.Time.Time)
+	assert.Equal(t, 90*time.Second+500*time.Millisecond, st.Age())
+	assert.Equal(t, time.Date(2021, 6, 20, 11, 18, 55, 500000000, time.UTC), st.ServerTime())
+	assert.True(t, SensiboTime{SecondsAgo: 10}.ServerTime().IsZero())
+}
+
+func TestHistoricalMeasurements_JSON(t *testing.T) {
+	h := HistoricalMeasurements{}
+	body := `{"temperature":[{"time":"2021-06-20T11:00:00Z","value":24.5}],"humidity":[]}`
+
+	assert.NoError(t, json.Unmarshal([]byte(body), &h))
+	assert.Equal(t, []Measurement{{Time: ParseTimestamp("2021-06-20T11:00:00Z"), Value: 24.5}}, h.Temperature)
+	assert.Equal(t, time.Date(2021, 6, 20, 11, 0, 0, 0, time.UTC), h.Temperature[0].Time.Time)
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Sensibo API.
+	DefaultBaseURL = "https://home.sensibo.com/api"
+	// DefaultMaxResponseSize is the default limit on the size of response bodies.
+	DefaultMaxResponseSize = 32 << 20
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the maximum size.
+var ErrResponseTooLarge = errors.New("sensibo: response too large")
+
+// Option configures a Sensibo client created with NewClient.
+type Option func(*Sensibo)
+
+// WithBaseURL sets the base URL that all requests are sent to.
+//
+// baseURL should not include the API version, e.g: "https://home.sensibo.com/api"
+func WithBaseURL(baseURL string) Option {
+	return func(s *Sensibo) {
+		s.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the client used for http requests (e.g: http.DefaultClient).
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(s *Sensibo) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(s *Sensibo) {
+		s.userAgent = userAgent
+	}
+}
+
+// WithDefaultTimeout sets a timeout for requests whose context has no deadline.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(s *Sensibo) {
+		s.timeout = timeout
+	}
+}
+
+// WithHeaders adds extra headers that are sent with every request.
+//
+// Calling it more than once merges the headers, later values win.
+func WithHeaders(headers map[string]string) Option {
+	return func(s *Sensibo) {
+		if s.headers == nil {
+			s.headers = http.Header{}
+		}
+
+		for k, v := range headers {
+			s.headers.Set(k, v)
+		}
+	}
+}
+
+// WithMaxResponseSize limits the size in bytes of response bodies,
+// larger responses fail with ErrResponseTooLarge.
+//
+// A size of 0 or less uses DefaultMaxResponseSize.
+func WithMaxResponseSize(size int64) Option {
+	return func(s *Sensibo) {
+		if size <= 0 {
+			size = DefaultMaxResponseSize
+		}
+
+		s.maxResponseSize = size
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	httpClient := &mocks.HTTPClientMock{}
+
+	got := NewClient(
+		"api-key",
+		WithBaseURL("http://localhost:8080/api/"),
+		WithHTTPClient(httpClient),
+		WithUserAgent("my-agent"),
+		WithDefaultTimeout(time.Second),
+		WithHeaders(map[string]string{"X-A": "a"}),
+		WithHeaders(map[string]string{"X-B": "b"}),
+		WithMaxResponseSize(1024),
+	)
+
+	assert.Equal(t, &Sensibo{
+		credentials: StaticCredentials("api-key"),
+		httpClient:  httpClient,
+		baseURL:     "http://localhost:8080/api",
+		userAgent:   "my-agent",
+		timeout:     time.Second,
+		headers:     http.Header{"X-A": []string{"a"}, "X-B": []string{"b"}},
+
+		maxResponseSize: 1024,
+	}, got)
+}
+
+func TestWithMaxResponseSize_default(t *testing.T) {
+	for _, size := range []int64{0, -1} {
+		s := newStatusClient([]int{200}, WithMaxResponseSize(size))
+
+		assert.Equal(t, int64(DefaultMaxResponseSize), s.maxResponseSize)
+		assert.NoError(t, s.DeleteDeviceTimer(context.Background(), "1234"))
+	}
+}
+
+func TestOptions_appliedToRequests(t *testing.T) {
+	var gotReq *http.Request
+
+	s := NewClient(
+		"api-key",
+		WithBaseURL("http://localhost:8080/api"),
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				gotReq = req
+
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
+					StatusCode: 200,
+				}, nil
+			},
+		}),
+		WithUserAgent("my-agent"),
+		WithDefaultTimeout(time.Minute),
+		WithHeaders(map[string]string{"X-Custom": "value"}),
+	)
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/api/v1/pods/1234/timer?apiKey=api-key", gotReq.URL.String())
+	assert.Equal(t, "my-agent", gotReq.Header.Get("User-Agent"))
+	assert.Equal(t, "value", gotReq.Header.Get("X-Custom"))
+
+	_, hasDeadline := gotReq.Context().Deadline()
+
+	assert.True(t, hasDeadline)
+}
+
+func TestWithDefaultTimeout_keepsCallerDeadline(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+
+	s := NewClient(
+		"api-key",
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				got, _ := req.Context().Deadline()
+
+				assert.Equal(t, deadline, got)
+
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
+					StatusCode: 200,
+				}, nil
+			},
+		}),
+		WithDefaultTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	err := s.DeleteDeviceTimer(ctx, "1234")
+
+	assert.NoError(t, err)
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/odinn1984/go-sensibo/models"
 )
 
 // SetDeviceACStatePropertyPayload is the payload for SetDeviceACStateProperty API call
@@ -20,9 +22,28 @@ type SetDeviceACStatePropertyPayload struct {
 //
 // id is the ID of the device
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) SetDeviceACStateProperty(ctx context.Context, id string, property string, value string) (string, error) {
+// Target temperatures may be fractional (e.g: "22.5"), they are sent with
+// at most one decimal so "22.0" is sent as "22". With WithValidation they
+// are rounded to whole degrees for devices that only support whole ones.
+//
+// It returns the new AC state or error if an issue occurred
+func (s *Sensibo) SetDeviceACStateProperty(ctx context.Context, id string, property string, value string) (*models.ACState, error) {
+	if property == "targetTemperature" {
+		if temperature, ok := parseTemperature(value); ok {
+			value = models.FormatTemperature(temperature)
+		}
+	}
+
+	if s.validation != nil {
+		validated, err := s.validateACStateProperty(ctx, id, property, value)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed updating property: \n\t%w", err)
+		}
+
+		value = validated
+	}
+
 	payload := SetDeviceACStatePropertyPayload{
 		NewValue: value,
 	}
@@ -30,19 +51,23 @@ func (s *Sensibo) SetDeviceACStateProperty(ctx context.Context, id string, prope
 	payloadStr, err := json.Marshal(payload)
 
 	if err != nil {
-		return "", fmt.Errorf("failed marshal on payload: \n\t%v", err)
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
 	}
 
-	resp, err := s.makePatchRequest(
+	result := models.ACState{}
+
+	err = s.makePatchRequest(
 		ctx,
+		operation{"SetDeviceACStateProperty", id},
 		"v2",
 		fmt.Sprintf("pods/%s/acStates/%s", id, property),
 		bytes.NewBuffer(payloadStr),
+		&result,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed updating property: \n\t%v", err)
+		return nil, fmt.Errorf("failed updating property: \n\t%w", err)
 	}
 
-	return resp, nil
+	return &result, nil
 }
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/odinn1984/go-sensibo/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,8 +28,8 @@ func TestSensibo_SetDeviceACStateProperty(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		want   *models.ACState
+		err    string
 	}{
 		{
 			name: "successful execution",
@@ -40,12 +41,12 @@ func TestSensibo_SetDeviceACStateProperty(t *testing.T) {
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1"}}`)),
 					StatusCode: 200,
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			want: &models.ACState{ID: "1"},
+			err:  "",
 		},
 		{
 			name: "returns an error or request failure: status code not 200",
@@ -61,8 +62,8 @@ func TestSensibo_SetDeviceACStateProperty(t *testing.T) {
 					StatusCode: 301,
 				}, nil
 			},
-			want: "",
-			err:  fmt.Errorf("failed updating property: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			want: nil,
+			err:  "failed updating property: \n\tsensibo: PATCH v2/pods/1234/acStates/prop: 301 Moved Permanently",
 		},
 		{
 			name: "returns an error or request failure: returned err not nil",
@@ -78,8 +79,8 @@ func TestSensibo_SetDeviceACStateProperty(t *testing.T) {
 					StatusCode: 301,
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed updating property: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: Error"),
+			want: nil,
+			err:  "failed updating property: \n\tsensibo: PATCH v2/pods/1234/acStates/prop: Error",
 		},
 	}
 	for _, tt := range tests {
@@ -94,7 +95,26 @@ func TestSensibo_SetDeviceACStateProperty(t *testing.T) {
 			got, err := s.SetDeviceACStateProperty(tt.args.ctx, tt.args.id, tt.args.property, tt.args.value)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
+
+func TestSensibo_SetDeviceACStateProperty_targetTemperature(t *testing.T) {
+	for value, sent := range map[string]string{"22": "22", "22.0": "22", "22.04": "22", "warm": "warm"} {
+		router := mocks.NewRouter()
+		router.Expect(http.MethodPatch, "v2/pods/{id}/acStates/targetTemperature").
+			WithJSONBody(fmt.Sprintf(`{"newValue":%q}`, sent)).
+			ReturnResult(map[string]string{"status": "Success"})
+
+		s := NewClient("api-key", WithHTTPClient(router.Client()))
+		_, err := s.SetDeviceACStateProperty(context.Background(), "1234", "targetTemperature", value)
+
+		assert.NoError(t, err, "value %s", value)
+		router.Verify(t)
+	}
+}
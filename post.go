@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/odinn1984/go-sensibo/models"
@@ -18,71 +19,148 @@ type SetDeviceACStatePayload struct {
 	ACState models.ACStateData `json:"acState"`
 }
 
+// ErrEmptyACStatePatch is returned by UpdateDeviceACState when the patch sets no properties.
+var ErrEmptyACStatePatch = errors.New("sensibo: ac state patch is empty")
+
 // SetDeviceACState sets the AC state of the device.
 //
 // id is the ID of the device
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) SetDeviceACState(ctx context.Context, id string, state models.ACStateData) (string, error) {
+// Every property of state is sent, use UpdateDeviceACState to change
+// only some of them.
+//
+// With WithValidation, fractional target temperatures are rounded to whole
+// degrees when the device only supports whole ones.
+//
+// It returns the new AC state or error if an issue occurred
+func (s *Sensibo) SetDeviceACState(ctx context.Context, id string, state models.ACStateData) (*models.ACState, error) {
+	state.TargetTemperature = models.NormalizeTemperature(state.TargetTemperature)
+
+	if s.validation != nil {
+		validated, err := s.validateACState(ctx, id, state)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed setting device ac state: \n\t%w", err)
+		}
+
+		state = validated
+	}
+
 	payload := SetDeviceACStatePayload{state}
 	payloadStr, err := json.Marshal(payload)
 
 	if err != nil {
-		return "", fmt.Errorf("failed marshal on payload: \n\t%v", err)
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
 	}
 
-	resp, err := s.makePostRequest(
+	result := models.ACState{}
+
+	err = s.makePostRequest(
 		ctx,
+		operation{"SetDeviceACState", id},
 		"v2",
 		fmt.Sprintf("pods/%s/acStates", id),
 		bytes.NewBuffer(payloadStr),
+		&result,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed setting device ac state: \n\t%v", err)
+		return nil, fmt.Errorf("failed setting device ac state: \n\t%w", err)
 	}
 
-	return resp, nil
+	return &result, nil
 }
 
-// CreateDeviceSchedulePayload is the payload for the CreateDeviceSchedule API
-type CreateDeviceSchedulePayload struct {
-	TargetTimeLocal string             `json:"targetTimeLocal"`
-	TimeZone        string             `json:"timezone"`
-	ACState         models.ACStateData `json:"acState"`
-	RecurringDays   []string           `json:"recurOnDaysOfWeek"`
+// UpdateDeviceACStatePayload is the payload for the UpdateDeviceACState API
+type UpdateDeviceACStatePayload struct {
+	ACState models.ACStatePatch `json:"acState"`
 }
 
-// CreateDeviceSchedule creates a new schedule.
+// UpdateDeviceACState changes only the properties of the AC state that are set in patch.
 //
 // id is the ID of the device
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) CreateDeviceSchedule(ctx context.Context, id string, schedule CreateDeviceSchedulePayload) (string, error) {
-	payload := CreateDeviceSchedulePayload{
-		schedule.TargetTimeLocal,
-		schedule.TimeZone,
-		schedule.ACState,
-		schedule.RecurringDays,
+// It returns the new AC state or error if an issue occurred
+func (s *Sensibo) UpdateDeviceACState(ctx context.Context, id string, patch models.ACStatePatch) (*models.ACState, error) {
+	if patch.IsEmpty() {
+		return nil, fmt.Errorf("failed updating device ac state: \n\t%w", ErrEmptyACStatePatch)
 	}
+
+	if patch.TargetTemperature != nil {
+		patch = patch.WithTargetTemperature(models.NormalizeTemperature(*patch.TargetTemperature))
+	}
+
+	if s.validation != nil {
+		validated, err := s.validateACStatePatch(ctx, id, patch)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed updating device ac state: \n\t%w", err)
+		}
+
+		patch = validated
+	}
+
+	payload := UpdateDeviceACStatePayload{patch}
 	payloadStr, err := json.Marshal(payload)
 
 	if err != nil {
-		return "", fmt.Errorf("failed marshal on payload: \n\t%v", err)
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
 	}
 
-	resp, err := s.makePostRequest(
+	result := models.ACState{}
+
+	err = s.makePostRequest(
+		ctx,
+		operation{"UpdateDeviceACState", id},
+		"v2",
+		fmt.Sprintf("pods/%s/acStates", id),
+		bytes.NewBuffer(payloadStr),
+		&result,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed updating device ac state: \n\t%w", err)
+	}
+
+	return &result, nil
+}
+
+// CreateDeviceSchedulePayload is the payload for the CreateDeviceSchedule API
+type CreateDeviceSchedulePayload = models.CreateDeviceSchedulePayload
+
+// CreateDeviceSchedule creates a new schedule.
+//
+// id is the ID of the device
+//
+// It returns the created schedule or error if an issue occurred
+func (s *Sensibo) CreateDeviceSchedule(ctx context.Context, id string, schedule CreateDeviceSchedulePayload) (*models.DeviceSchedule, error) {
+	state, err := s.prepareTemperature(ctx, id, schedule.ACState)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed creating a schedule: \n\t%w", err)
+	}
+
+	schedule.ACState = state
+	payloadStr, err := json.Marshal(schedule)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
+	}
+
+	result := models.DeviceSchedule{}
+
+	err = s.makePostRequest(
 		ctx,
+		operation{"CreateDeviceSchedule", id},
 		"v1",
 		fmt.Sprintf("pods/%s/schedules", id),
 		bytes.NewBuffer(payloadStr),
+		&result,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed creating a schedule: \n\t%v", err)
+		return nil, fmt.Errorf("failed creating a schedule: \n\t%w", err)
 	}
 
-	return resp, nil
+	return &result, nil
 }
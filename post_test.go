@@ -27,8 +27,8 @@ func TestSensibo_SetDeviceACState(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		want   *models.ACState
+		err    string
 	}{
 		{
 			name: "successful execution",
@@ -39,12 +39,28 @@ func TestSensibo_SetDeviceACState(t *testing.T) {
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1"}}`)),
 					StatusCode: 200,
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			want: &models.ACState{ID: "1"},
+			err:  "",
+		},
+		{
+			name: "returns an error when response status is not success",
+			args: args{
+				ctx:   context.Background(),
+				id:    "1234",
+				state: models.ACStateData{},
+			},
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"failed","reason":"Busy"}`)),
+					StatusCode: 200,
+				}, nil
+			},
+			want: nil,
+			err:  "failed setting device ac state: \n\tsensibo: response status \"failed\": Busy",
 		},
 		{
 			name: "returns an error or request failure: status code not 200",
@@ -59,8 +75,8 @@ func TestSensibo_SetDeviceACState(t *testing.T) {
 					StatusCode: 301,
 				}, nil
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting device ac state: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			want: nil,
+			err:  "failed setting device ac state: \n\tsensibo: POST v2/pods/1234/acStates: 301 Moved Permanently",
 		},
 		{
 			name: "returns an error or request failure: returned err not nil",
@@ -75,8 +91,8 @@ func TestSensibo_SetDeviceACState(t *testing.T) {
 					StatusCode: 301,
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting device ac state: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: Error"),
+			want: nil,
+			err:  "failed setting device ac state: \n\tsensibo: POST v2/pods/1234/acStates: Error",
 		},
 	}
 
@@ -92,7 +108,75 @@ func TestSensibo_SetDeviceACState(t *testing.T) {
 			got, err := s.SetDeviceACState(tt.args.ctx, tt.args.id, tt.args.state)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSensibo_UpdateDeviceACState(t *testing.T) {
+	tests := []struct {
+		name   string
+		patch  models.ACStatePatch
+		DoMock func(req *http.Request) (*http.Response, error)
+		want   *models.ACState
+		err    string
+	}{
+		{
+			name:  "sends only the set properties",
+			patch: models.ACStatePatch{}.WithOn(false).WithLight(models.LightOff),
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(req.Body)
+
+				if string(body) != `{"acState":{"on":false,"light":"off"}}` {
+					return nil, fmt.Errorf("unexpected body %s", body)
+				}
+
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1"}}`)),
+					StatusCode: 200,
+				}, nil
+			},
+			want: &models.ACState{ID: "1"},
+			err:  "",
+		},
+		{
+			name:  "returns an error when the patch is empty",
+			patch: models.ACStatePatch{},
+			want:  nil,
+			err:   "failed updating device ac state: \n\tsensibo: ac state patch is empty",
+		},
+		{
+			name:  "returns an error or request failure: returned err not nil",
+			patch: models.ACStatePatch{}.WithOn(true),
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return nil, fmt.Errorf("Error")
+			},
+			want: nil,
+			err:  "failed updating device ac state: \n\tsensibo: POST v2/pods/1234/acStates: Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(
+				&mocks.HTTPClientMock{
+					DoMock: tt.DoMock,
+				},
+				"api-key",
+			)
+
+			got, err := s.UpdateDeviceACState(context.Background(), "1234", tt.patch)
+
+			assert.Equal(t, tt.want, got)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
@@ -107,8 +191,8 @@ func TestSensibo_CreateDeviceSchedule(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		want   *models.DeviceSchedule
+		err    string
 	}{
 		{
 			name: "successful execution",
@@ -119,12 +203,12 @@ func TestSensibo_CreateDeviceSchedule(t *testing.T) {
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1"}}`)),
 					StatusCode: 200,
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			want: &models.DeviceSchedule{ID: "1"},
+			err:  "",
 		},
 		{
 			name: "returns an error or request failure: status code not 200",
@@ -139,8 +223,8 @@ func TestSensibo_CreateDeviceSchedule(t *testing.T) {
 					StatusCode: 301,
 				}, nil
 			},
-			want: "",
-			err:  fmt.Errorf("failed creating a schedule: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			want: nil,
+			err:  "failed creating a schedule: \n\tsensibo: POST v1/pods/1234/schedules: 301 Moved Permanently",
 		},
 		{
 			name: "returns an error or request failure: returned err not nil",
@@ -155,8 +239,8 @@ func TestSensibo_CreateDeviceSchedule(t *testing.T) {
 					StatusCode: 301,
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed creating a schedule: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: Error"),
+			want: nil,
+			err:  "failed creating a schedule: \n\tsensibo: POST v1/pods/1234/schedules: Error",
 		},
 	}
 
@@ -172,7 +256,11 @@ func TestSensibo_CreateDeviceSchedule(t *testing.T) {
 			got, err := s.CreateDeviceSchedule(tt.args.ctx, tt.args.id, tt.args.schedule)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
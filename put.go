@@ -26,29 +26,38 @@ type SetDeviceTimerPayload struct {
 //
 // id is the ID of the device and state is of type #models.DeviceTimer
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) SetDeviceTimer(ctx context.Context, id string, minutesFromNow int, state models.ACStateData) (string, error) {
+// It returns the timer that was set or error if an issue occurred
+func (s *Sensibo) SetDeviceTimer(ctx context.Context, id string, minutesFromNow int, state models.ACStateData) (*models.DeviceTimer, error) {
+	state, err := s.prepareTemperature(ctx, id, state)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed setting timer: \n\t%w", err)
+	}
+
 	payload := SetDeviceTimerPayload{minutesFromNow, state}
 
 	payloadStr, err := json.Marshal(payload)
 
 	if err != nil {
-		return "", fmt.Errorf("failed marshal on payload: \n\t%v", err)
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
 	}
 
-	resp, err := s.makePutRequest(
+	result := models.DeviceTimer{}
+
+	err = s.makePutRequest(
 		ctx,
+		operation{"SetDeviceTimer", id},
 		"v1",
 		fmt.Sprintf("pods/%s/timer", id),
 		bytes.NewBuffer(payloadStr),
+		&result,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed setting timer: \n\t%v", err)
+		return nil, fmt.Errorf("failed setting timer: \n\t%w", err)
 	}
 
-	return resp, nil
+	return &result, nil
 }
 
 // ToggleDeviceClimateReactPayload is the payload for ToggleDeviceClimateReact API call
@@ -60,28 +69,31 @@ type ToggleDeviceClimateReactPayload struct {
 //
 // id is the ID of the device
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) ToggleDeviceClimateReact(ctx context.Context, id string, enabled bool) (string, error) {
+// It returns the updated climate react settings or error if an issue occurred
+func (s *Sensibo) ToggleDeviceClimateReact(ctx context.Context, id string, enabled bool) (*models.ClimateReact, error) {
 	payload := ToggleDeviceClimateReactPayload{enabled}
 	payloadStr, err := json.Marshal(payload)
 
 	if err != nil {
-		return "", fmt.Errorf("failed marshal on payload: \n\t%v", err)
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
 	}
 
-	resp, err := s.makePutRequest(
+	result := models.ClimateReact{}
+
+	err = s.makePutRequest(
 		ctx,
+		operation{"ToggleDeviceClimateReact", id},
 		"v2",
 		fmt.Sprintf("pods/%s/smartmode", id),
 		bytes.NewBuffer(payloadStr),
+		&result,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed setting climate react: \n\t%v", err)
+		return nil, fmt.Errorf("failed setting climate react: \n\t%w", err)
 	}
 
-	return resp, nil
+	return &result, nil
 }
 
 // ToggleDeviceSchedulePayload is the payload for the ToggleDeviceSchedule API call
@@ -91,26 +103,29 @@ type ToggleDeviceSchedulePayload struct {
 
 // ToggleDeviceSchedule toggles a device schedule state on or off.
 //
-// It returns the direct response from Sensibo API as a string or error
-// if an issue occurred
-func (s *Sensibo) ToggleDeviceSchedule(ctx context.Context, deviceID string, scheduleID string, enabled bool) (string, error) {
+// It returns the updated schedule or error if an issue occurred
+func (s *Sensibo) ToggleDeviceSchedule(ctx context.Context, deviceID string, scheduleID string, enabled bool) (*models.DeviceSchedule, error) {
 	payload := ToggleDeviceSchedulePayload{enabled}
 	payloadStr, err := json.Marshal(payload)
 
 	if err != nil {
-		return "", fmt.Errorf("failed marshal on payload: \n\t%v", err)
+		return nil, fmt.Errorf("failed marshal on payload: \n\t%w", err)
 	}
 
-	resp, err := s.makePutRequest(
+	result := models.DeviceSchedule{}
+
+	err = s.makePutRequest(
 		ctx,
+		operation{"ToggleDeviceSchedule", deviceID},
 		"v1",
 		fmt.Sprintf("pods/%s/schedules/%s", deviceID, scheduleID),
 		bytes.NewBuffer(payloadStr),
+		&result,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed setting climate react: \n\t%v", err)
+		return nil, fmt.Errorf("failed setting climate react: \n\t%w", err)
 	}
 
-	return resp, nil
+	return &result, nil
 }
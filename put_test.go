@@ -28,8 +28,8 @@ func TestSensibo_SetDeviceTimer(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		want   *models.DeviceTimer
+		err    string
 	}{
 		{
 			name: "successful execution",
@@ -41,12 +41,12 @@ func TestSensibo_SetDeviceTimer(t *testing.T) {
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1"}}`)),
 					StatusCode: 200,
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			want: &models.DeviceTimer{ID: "1"},
+			err:  "",
 		},
 		{
 			name: "returns an error or request failure: status code not 200",
@@ -62,8 +62,8 @@ func TestSensibo_SetDeviceTimer(t *testing.T) {
 					StatusCode: 301,
 				}, nil
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting timer: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			want: nil,
+			err:  "failed setting timer: \n\tsensibo: PUT v1/pods/1234/timer: 301 Moved Permanently",
 		},
 		{
 			name: "returns an error or request failure: returned err not nil",
@@ -79,8 +79,8 @@ func TestSensibo_SetDeviceTimer(t *testing.T) {
 					StatusCode: 301,
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting timer: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: Error"),
+			want: nil,
+			err:  "failed setting timer: \n\tsensibo: PUT v1/pods/1234/timer: Error",
 		},
 	}
 
@@ -96,7 +96,11 @@ func TestSensibo_SetDeviceTimer(t *testing.T) {
 			got, err := s.SetDeviceTimer(tt.args.ctx, tt.args.id, tt.args.minutesFromNow, tt.args.state)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
@@ -111,8 +115,8 @@ func TestSensibo_ToggleDeviceClimateReact(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		want   *models.ClimateReact
+		err    string
 	}{
 		{
 			name: "successful execution",
@@ -123,12 +127,12 @@ func TestSensibo_ToggleDeviceClimateReact(t *testing.T) {
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"enabled":true}}`)),
 					StatusCode: 200,
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			want: &models.ClimateReact{Enabled: true},
+			err:  "",
 		},
 		{
 			name: "returns an error or request failure: status code not 200",
@@ -143,8 +147,8 @@ func TestSensibo_ToggleDeviceClimateReact(t *testing.T) {
 					StatusCode: 301,
 				}, nil
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting climate react: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			want: nil,
+			err:  "failed setting climate react: \n\tsensibo: PUT v2/pods/1234/smartmode: 301 Moved Permanently",
 		},
 		{
 			name: "returns an error or request failure: returned err not nil",
@@ -159,8 +163,8 @@ func TestSensibo_ToggleDeviceClimateReact(t *testing.T) {
 					StatusCode: 301,
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting climate react: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: Error"),
+			want: nil,
+			err:  "failed setting climate react: \n\tsensibo: PUT v2/pods/1234/smartmode: Error",
 		},
 	}
 
@@ -176,7 +180,11 @@ func TestSensibo_ToggleDeviceClimateReact(t *testing.T) {
 			got, err := s.ToggleDeviceClimateReact(tt.args.ctx, tt.args.id, tt.args.enabled)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
@@ -192,8 +200,8 @@ func TestSensibo_ToggleDeviceSchedule(t *testing.T) {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
-		want   string
-		err    error
+		want   *models.DeviceSchedule
+		err    string
 	}{
 		{
 			name: "successful execution",
@@ -205,12 +213,12 @@ func TestSensibo_ToggleDeviceSchedule(t *testing.T) {
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1"}}`)),
 					StatusCode: 200,
 				}, nil
 			},
-			want: "Success",
-			err:  nil,
+			want: &models.DeviceSchedule{ID: "1"},
+			err:  "",
 		},
 		{
 			name: "returns an error or request failure: status code not 200",
@@ -226,8 +234,8 @@ func TestSensibo_ToggleDeviceSchedule(t *testing.T) {
 					StatusCode: 301,
 				}, nil
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting climate react: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			want: nil,
+			err:  "failed setting climate react: \n\tsensibo: PUT v1/pods/1234/schedules/1234: 301 Moved Permanently",
 		},
 		{
 			name: "returns an error or request failure: returned err not nil",
@@ -243,8 +251,8 @@ func TestSensibo_ToggleDeviceSchedule(t *testing.T) {
 					StatusCode: 301,
 				}, fmt.Errorf("Error")
 			},
-			want: "",
-			err:  fmt.Errorf("failed setting climate react: \n\tfailed making request \n\tCode: 301 \n\tMsg:  \n\tErr: Error"),
+			want: nil,
+			err:  "failed setting climate react: \n\tsensibo: PUT v1/pods/1234/schedules/1234: Error",
 		},
 	}
 
@@ -260,7 +268,25 @@ func TestSensibo_ToggleDeviceSchedule(t *testing.T) {
 			got, err := s.ToggleDeviceSchedule(tt.args.ctx, tt.args.deviceID, tt.args.scheduleID, tt.args.enabled)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
+
+func TestSensibo_ToggleDeviceSchedule_router(t *testing.T) {
+	router := mocks.NewRouter()
+	router.Expect(http.MethodPut, "v1/pods/{id}/schedules/{sid}").
+		WithJSONBody(ToggleDeviceSchedulePayload{IsEnabled: true}).
+		ReturnResult(map[string]interface{}{"id": "5678", "isEnabled": true})
+
+	s := New(router.Client(), "api-key")
+	got, err := s.ToggleDeviceSchedule(context.Background(), "1234", "5678", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.DeviceSchedule{ID: "5678", IsEnabled: true}, got)
+	router.Verify(t)
+}
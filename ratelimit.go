@@ -0,0 +1,178 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a client-side token bucket limiter.
+//
+// It has an optional global budget shared by all requests and optional
+// per-endpoint budgets. It is safe for concurrent use and the same
+// RateLimiter can be shared between several Sensibo instances that use
+// the same API key, see WithRateLimiter.
+type RateLimiter struct {
+	global    *tokenBucket
+	mu        sync.RWMutex
+	endpoints []endpointLimit
+}
+
+type endpointLimit struct {
+	method  string
+	pattern string
+	bucket  *tokenBucket
+}
+
+// NewRateLimiter creates a limiter allowing rate requests per second on
+// average with bursts of up to burst requests.
+//
+// A rate of 0 or less disables the global budget, which is useful when
+// only per-endpoint budgets are needed.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	l := &RateLimiter{}
+
+	if rate > 0 {
+		l.global = newTokenBucket(rate, burst)
+	}
+
+	return l
+}
+
+// SetEndpointLimit adds a budget for requests matching method and pattern.
+//
+// pattern is matched against the endpoint without the API version using
+// path.Match, e.g: "pods/*/acStates". An empty method matches all methods.
+// Requests must fit in both the global budget and every matching endpoint budget.
+//
+// It returns the limiter to allow chaining.
+func (l *RateLimiter) SetEndpointLimit(method string, pattern string, rate float64, burst int) *RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.endpoints = append(l.endpoints, endpointLimit{
+		method:  method,
+		pattern: pattern,
+		bucket:  newTokenBucket(rate, burst),
+	})
+
+	return l
+}
+
+// Wait blocks until a request to endpoint is allowed or ctx is done.
+//
+// The tokens of all matching budgets are taken together and are all
+// given back when ctx is done before the request is allowed.
+func (l *RateLimiter) Wait(ctx context.Context, method string, endpoint string) error {
+	buckets := []*tokenBucket{}
+
+	if l.global != nil {
+		buckets = append(buckets, l.global)
+	}
+
+	l.mu.RLock()
+	for _, e := range l.endpoints {
+		if e.method != "" && e.method != method {
+			continue
+		}
+
+		if ok, _ := path.Match(e.pattern, endpoint); ok {
+			buckets = append(buckets, e.bucket)
+		}
+	}
+	l.mu.RUnlock()
+
+	reserved := make([]*tokenBucket, 0, len(buckets))
+	delay := time.Duration(0)
+
+	for _, b := range buckets {
+		if b.rate <= 0 {
+			continue
+		}
+
+		reserved = append(reserved, b)
+
+		if d := b.reserve(); d > delay {
+			delay = d
+		}
+	}
+
+	if delay == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		for _, b := range reserved {
+			b.cancel()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// WithRateLimiter makes the client wait for limiter before sending every request.
+//
+// Pass the same limiter to all clients sharing an API key.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(s *Sensibo) {
+		s.rateLimiter = limiter
+	}
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait
+// before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	b.last = now
+
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+}
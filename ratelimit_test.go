@@ -0,0 +1,119 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(20, 2)
+
+	start := time.Now()
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, l.Wait(context.Background(), http.MethodGet, "pods/1234"))
+	}
+
+	// 2 requests fit in the burst, the other 2 wait 50ms each
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+}
+
+func TestRateLimiter_endpointLimit(t *testing.T) {
+	l := NewRateLimiter(0, 0).SetEndpointLimit(http.MethodPost, "pods/*/acStates", 1, 1)
+
+	assert.NoError(t, l.Wait(context.Background(), http.MethodPost, "pods/1234/acStates"))
+
+	// other endpoints and methods are not limited
+	assert.NoError(t, l.Wait(context.Background(), http.MethodGet, "pods/1234/acStates"))
+	assert.NoError(t, l.Wait(context.Background(), http.MethodPost, "pods/1234/schedules"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := l.Wait(ctx, http.MethodPost, "pods/5678/acStates")
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRateLimiter_cancelledWaitReturnsToken(t *testing.T) {
+	l := NewRateLimiter(10, 1)
+
+	assert.NoError(t, l.Wait(context.Background(), http.MethodGet, "pods"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Error(t, l.Wait(ctx, http.MethodGet, "pods"))
+
+	start := time.Now()
+
+	assert.NoError(t, l.Wait(context.Background(), http.MethodGet, "pods"))
+	assert.True(t, time.Since(start) < 150*time.Millisecond)
+}
+
+func TestRateLimiter_failedEndpointWaitReturnsGlobalToken(t *testing.T) {
+	l := NewRateLimiter(1, 2).SetEndpointLimit("", "pods/*/acStates", 1, 1)
+
+	assert.NoError(t, l.Wait(context.Background(), http.MethodPost, "pods/1234/acStates"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := l.Wait(ctx, http.MethodPost, "pods/1234/acStates")
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// the global token taken by the failed wait was given back
+	start := time.Now()
+
+	assert.NoError(t, l.Wait(context.Background(), http.MethodGet, "pods/1234"))
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+}
+
+func TestWithRateLimiter_sharedBetweenClients(t *testing.T) {
+	l := NewRateLimiter(50, 1)
+	httpClient := &mocks.HTTPClientMock{
+		DoMock: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
+				StatusCode: 200,
+			}, nil
+		},
+	}
+	clients := []*Sensibo{
+		NewClient("api-key", WithHTTPClient(httpClient), WithRateLimiter(l)),
+		NewClient("api-key", WithHTTPClient(httpClient), WithRateLimiter(l)),
+	}
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+
+		go func(s *Sensibo) {
+			defer wg.Done()
+
+			err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+			assert.NoError(t, err)
+		}(clients[i%2])
+	}
+
+	wg.Wait()
+
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+}
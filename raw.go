@@ -0,0 +1,92 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Do makes a request to any endpoint of the Sensibo API.
+//
+// It is an escape hatch for endpoints that don't have a dedicated method yet,
+// and goes through the same URL building, authentication, headers, retries,
+// middleware and response envelope decoding as every other method.
+//
+// version is the API version (e.g: "v2") and endpoint the path after it
+// (e.g: "pods/1234/acStates").
+//
+// query holds extra query parameters, it can be nil.
+//
+// body is sent as is if it is an io.Reader or a []byte, it is marshaled
+// to JSON otherwise. Use nil for no body.
+//
+// out must be a pointer, the result of the response envelope is decoded
+// into it. Use nil to discard the result.
+func (s *Sensibo) Do(
+	ctx context.Context,
+	method string,
+	version string,
+	endpoint string,
+	query map[string]string,
+	body interface{},
+	out interface{},
+) error {
+	if out != nil {
+		if rv := reflect.ValueOf(out); rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return fmt.Errorf("out must be a non-nil pointer, got %T", out)
+		}
+	}
+
+	var reader io.Reader
+
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		payloadStr, err := json.Marshal(b)
+
+		if err != nil {
+			return fmt.Errorf("failed marshal on payload: \n\t%w", err)
+		}
+
+		reader = bytes.NewReader(payloadStr)
+	}
+
+	err := s.makeRequest(ctx, request{
+		op:       operation{"Do", deviceIDFromEndpoint(endpoint)},
+		method:   strings.ToUpper(method),
+		version:  version,
+		endpoint: strings.Trim(endpoint, "/"),
+		params:   query,
+		body:     reader,
+		result:   out,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed making request \n\t%w", err)
+	}
+
+	return nil
+}
+
+// deviceIDFromEndpoint returns the device ID of "pods/{id}/..." endpoints.
+func deviceIDFromEndpoint(endpoint string) string {
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+
+	if len(parts) >= 2 && parts[0] == "pods" {
+		return parts[1]
+	}
+
+	return ""
+}
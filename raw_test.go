@@ -0,0 +1,109 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensibo_Do(t *testing.T) {
+	type result struct {
+		Value int `json:"value"`
+	}
+
+	tests := []struct {
+		name     string
+		body     interface{}
+		out      interface{}
+		wantBody string
+		want     interface{}
+		err      string
+	}{
+		{
+			name:     "marshals body and decodes result",
+			body:     map[string]int{"value": 1},
+			out:      &result{},
+			wantBody: `{"value":1}`,
+			want:     &result{Value: 42},
+		},
+		{
+			name:     "sends raw bytes",
+			body:     []byte(`{"raw":true}`),
+			out:      &result{},
+			wantBody: `{"raw":true}`,
+			want:     &result{Value: 42},
+		},
+		{
+			name:     "sends readers as is",
+			body:     strings.NewReader(`{"reader":true}`),
+			wantBody: `{"reader":true}`,
+		},
+		{
+			name: "rejects non pointer out",
+			out:  result{},
+			want: result{},
+			err:  "out must be a non-nil pointer, got sensibo.result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq *http.Request
+			gotBody := ""
+
+			s := New(&mocks.HTTPClientMock{
+				DoMock: func(req *http.Request) (*http.Response, error) {
+					gotReq = req
+
+					if req.Body != nil {
+						b, _ := ioutil.ReadAll(req.Body)
+						gotBody = string(b)
+					}
+
+					return &http.Response{
+						Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"value":42}}`)),
+						StatusCode: 200,
+					}, nil
+				},
+			}, "api-key")
+
+			err := s.Do(
+				context.Background(),
+				"post",
+				"v2",
+				"/pods/1234/newEndpoint",
+				map[string]string{"a": "b"},
+				tt.body,
+				tt.out,
+			)
+
+			assert.Equal(t, tt.want, tt.out)
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.MethodPost, gotReq.Method)
+			assert.Equal(t, "https://home.sensibo.com/api/v2/pods/1234/newEndpoint?apiKey=api-key&a=b", gotReq.URL.String())
+			assert.Equal(t, tt.wantBody, gotBody)
+		})
+	}
+}
+
+func TestDeviceIDFromEndpoint(t *testing.T) {
+	assert.Equal(t, "1234", deviceIDFromEndpoint("pods/1234/acStates"))
+	assert.Equal(t, "1234", deviceIDFromEndpoint("/pods/1234"))
+	assert.Equal(t, "", deviceIDFromEndpoint("users/me/pods"))
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the API key anywhere it would be exposed.
+const redacted = "REDACTED"
+
+var apiKeyParamRegex = regexp.MustCompile(`(?i)(apiKey=)[^&\s"']*`)
+
+// WithAuthorizationHeader sends the API key in the Authorization header
+// (as a Bearer token) instead of the apiKey query parameter, so it never
+// appears in URLs.
+//
+// Only use it with hosts that accept the key this way.
+func WithAuthorizationHeader() Option {
+	return func(s *Sensibo) {
+		s.authHeader = true
+	}
+}
+
+// String implements fmt.Stringer without exposing the API key.
+func (s *Sensibo) String() string {
+	return fmt.Sprintf("Sensibo{BaseURL: %s}", s.baseURL)
+}
+
+// GoString implements fmt.GoStringer without exposing the API key.
+func (s *Sensibo) GoString() string {
+	return s.String()
+}
+
+// redact masks apiKey and any apiKey query parameter in str.
+func redact(str string, apiKey string) string {
+	str = apiKeyParamRegex.ReplaceAllString(str, fmt.Sprintf("${1}%s", redacted))
+
+	if apiKey != "" {
+		str = strings.ReplaceAll(str, apiKey, redacted)
+	}
+
+	return str
+}
+
+// redactError masks the API key in err while keeping it usable with errors.Is
+// and errors.As.
+func redactError(err error, apiKey string) error {
+	if err == nil {
+		return nil
+	}
+
+	var urlErr *url.Error
+
+	if errors.As(err, &urlErr) {
+		err = &url.Error{
+			Op:  urlErr.Op,
+			URL: redact(urlErr.URL, apiKey),
+			Err: urlErr.Err,
+		}
+	}
+
+	if msg := err.Error(); msg != redact(msg, apiKey) {
+		return &redactedError{msg: redact(msg, apiKey), err: err}
+	}
+
+	return err
+}
+
+func redactErrorResponse(errResp *ErrorResponse, apiKey string) *ErrorResponse {
+	if errResp == nil {
+		return nil
+	}
+
+	return &ErrorResponse{
+		Status:  errResp.Status,
+		Reason:  redact(errResp.Reason, apiKey),
+		Message: redact(errResp.Message, apiKey),
+	}
+}
+
+// redactEnvelopeError masks the API key in the response body kept by the
+// errors returned from decodeEnvelope.
+func redactEnvelopeError(err error, apiKey string) error {
+	switch e := err.(type) {
+	case *StatusError:
+		return &StatusError{
+			Status:  e.Status,
+			Reason:  redact(e.Reason, apiKey),
+			Message: redact(e.Message, apiKey),
+			Body:    redact(e.Body, apiKey),
+		}
+	case *DecodeError:
+		return &DecodeError{
+			Body: redact(e.Body, apiKey),
+			Err:  redactError(e.Err, apiKey),
+		}
+	default:
+		return redactError(err, apiKey)
+	}
+}
+
+// redactedError overrides the message of an error that contains the API key.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensibo_redactsTransportErrors(t *testing.T) {
+	s := New(
+		&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: fmt.Errorf("connection refused")}
+			},
+		},
+		"secret-key",
+	)
+
+	_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+	var urlErr *url.Error
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-key")
+	assert.Contains(t, err.Error(), "apiKey=REDACTED")
+	assert.True(t, errors.As(err, &urlErr))
+	assert.NotContains(t, urlErr.URL, "secret-key")
+}
+
+func TestSensibo_redactsErrorBody(t *testing.T) {
+	s := New(
+		&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"error","message":"bad key secret-key"}`)),
+					StatusCode: http.StatusUnauthorized,
+				}, nil
+			},
+		},
+		"secret-key",
+	)
+
+	_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+	var apiErr *APIError
+
+	assert.True(t, errors.As(err, &apiErr))
+	assert.NotContains(t, err.Error(), "secret-key")
+	assert.NotContains(t, apiErr.Body, "secret-key")
+	assert.Equal(t, "bad key REDACTED", apiErr.Response.Message)
+}
+
+func TestSensibo_redactsEnvelopeBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "unsuccessful status",
+			body: `{"status":"failed","reason":"secret-key","message":"bad key secret-key"}`,
+		},
+		{
+			name: "malformed body",
+			body: `{"status":"success","result":{"id":"secret-key"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(
+				&mocks.HTTPClientMock{
+					DoMock: func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							Body:       ioutil.NopCloser(strings.NewReader(tt.body)),
+							StatusCode: http.StatusOK,
+						}, nil
+					},
+				},
+				"secret-key",
+			)
+
+			_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+			var statusErr *StatusError
+			var decodeErr *DecodeError
+
+			assert.Error(t, err)
+			assert.NotContains(t, err.Error(), "secret-key")
+
+			if errors.As(err, &statusErr) {
+				assert.True(t, errors.Is(err, ErrUnsuccessfulStatus))
+				assert.NotContains(t, statusErr.Body, "secret-key")
+			} else {
+				assert.True(t, errors.As(err, &decodeErr))
+				assert.NotContains(t, decodeErr.Body, "secret-key")
+			}
+		})
+	}
+}
+
+func TestSensibo_String(t *testing.T) {
+	s := NewClient("secret-key")
+
+	assert.NotContains(t, fmt.Sprintf("%v %+v %#v %s", s, s, s, s), "secret-key")
+}
+
+func TestWithAuthorizationHeader(t *testing.T) {
+	var gotReq *http.Request
+
+	s := NewClient(
+		"secret-key",
+		WithAuthorizationHeader(),
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				gotReq = req
+
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{}}`)),
+					StatusCode: 200,
+				}, nil
+			},
+		}),
+	)
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://home.sensibo.com/api/v1/pods/1234/timer", gotReq.URL.String())
+	assert.Equal(t, "Bearer secret-key", gotReq.Header.Get("Authorization"))
+}
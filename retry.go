@@ -0,0 +1,155 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Only errors for which APIError.Retryable returns true are retried.
+// GET, PUT, PATCH and DELETE requests are retried, POST requests
+// are retried only when RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one,
+	// a value of 1 or less disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after every attempt.
+	Multiplier float64
+	// Jitter is the fraction (0 to 1) of the delay that is randomized.
+	Jitter float64
+	// RetryNonIdempotent enables retries for POST requests
+	// (e.g: SetDeviceACState and CreateDeviceSchedule).
+	RetryNonIdempotent bool
+	// OnRetry is called before waiting for the next attempt.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent holds information about a failed attempt that is about to be retried.
+type RetryEvent struct {
+	Operation string
+	DeviceID  string
+	Method    string
+	Version   string
+	Endpoint  string
+	// Attempt is the number of the failed attempt, starting at 1.
+	Attempt int
+	// Delay is how long the client waits before the next attempt.
+	Delay time.Duration
+	Err   error
+}
+
+// DefaultRetryPolicy returns a policy with sensible defaults:
+// 3 attempts, exponential backoff starting at 500ms up to 10s with 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy enables retries of failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *Sensibo) {
+		s.retryPolicy = policy
+	}
+}
+
+func (p RetryPolicy) shouldRetry(method string, attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if method == http.MethodPost && !p.RetryNonIdempotent {
+		return false
+	}
+
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
+
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	multiplier := p.Multiplier
+
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay*(1-jitter) + delay*jitter*rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// sleep waits for delay or until the context is done. It gives up right
+// away when the context deadline expires before the delay does.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses the Retry-After header which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFlakyClient(failures int, header http.Header, policy RetryPolicy) (*Sensibo, *int) {
+	calls := 0
+
+	return newMockClient(func(req *http.Request) (*http.Response, error) {
+		calls++
+
+		if calls <= failures {
+			return &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     header,
+			}, nil
+		}
+
+		body := []byte("{}")
+
+		if req.Body != nil {
+			body, _ = ioutil.ReadAll(req.Body)
+		}
+
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":` + string(body) + "}")),
+			StatusCode: http.StatusOK,
+		}, nil
+	}, WithRetryPolicy(policy)), &calls
+}
+
+func TestRetryPolicy_retriesIdempotentRequests(t *testing.T) {
+	events := []RetryEvent{}
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		OnRetry:        func(e RetryEvent) { events = append(events, e) },
+	}
+
+	s, calls := newFlakyClient(2, nil, policy)
+
+	got, err := s.ToggleDeviceClimateReact(context.Background(), "1234", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ClimateReact{Enabled: true}, got)
+	assert.Equal(t, 3, *calls)
+	assert.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].Attempt)
+	assert.Equal(t, "pods/1234/smartmode", events[0].Endpoint)
+	assert.True(t, errors.Is(events[0].Err, ErrServer))
+}
+
+func TestRetryPolicy_givesUpAfterMaxAttempts(t *testing.T) {
+	s, calls := newFlakyClient(5, nil, RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Equal(t, 2, *calls)
+}
+
+func TestRetryPolicy_postRequiresOptIn(t *testing.T) {
+	s, calls := newFlakyClient(1, nil, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	_, err := s.SetDeviceACState(context.Background(), "1234", models.ACStateData{})
+
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Equal(t, 1, *calls)
+
+	s, calls = newFlakyClient(1, nil, RetryPolicy{
+		MaxAttempts:        3,
+		InitialBackoff:     time.Millisecond,
+		RetryNonIdempotent: true,
+	})
+
+	_, err = s.SetDeviceACState(context.Background(), "1234", models.ACStateData{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestRetryPolicy_honorsRetryAfter(t *testing.T) {
+	var delay time.Duration
+
+	s, _ := newFlakyClient(1, http.Header{"Retry-After": []string{"0"}}, RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Hour,
+		OnRetry:        func(e RetryEvent) { delay = e.Delay },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := s.DeleteDeviceTimer(ctx, "1234")
+
+	// Retry-After of 0 falls back to the policy backoff which exceeds the deadline
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Equal(t, time.Hour, delay)
+
+	s, _ = newFlakyClient(1, http.Header{"Retry-After": []string{"1"}}, RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Hour,
+		OnRetry:        func(e RetryEvent) { delay = e.Delay },
+	})
+
+	err = s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1, nil))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3, nil))
+	assert.Equal(t, time.Second, p.backoff(10, nil))
+
+	p.Jitter = 0.5
+
+	for i := 0; i < 100; i++ {
+		got := p.backoff(1, nil)
+
+		assert.True(t, got >= 50*time.Millisecond && got <= 100*time.Millisecond)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("garbage"))
+
+	got := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+	assert.True(t, got > 58*time.Second && got <= time.Minute)
+}
@@ -5,19 +5,38 @@
 package sensibo
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sort"
+	"time"
 )
 
 // Sensibo holds all of the available functions to interact with the Sensibo API.
 type Sensibo struct {
-	APIKey     string
-	httpClient HTTPClient
+	credentials CredentialProvider
+	httpClient  HTTPClient
+	baseURL     string
+	userAgent   string
+	timeout     time.Duration
+	headers     http.Header
+
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	authHeader  bool
+	middleware  []Middleware
+	observers   []Observer
+	cache       *responseCache
+	flights     *flightGroup
+	breaker     *CircuitBreaker
+	validation  *ValidationConfig
+
+	maxResponseSize int64
 }
 
 // HTTPClient interface
@@ -35,10 +54,29 @@ type HTTPClient interface {
 //
 // It returns a pointed to Sensibo with the key already stored in it
 func New(httpClient HTTPClient, apikey string) *Sensibo {
-	return &Sensibo{
-		APIKey:     apikey,
-		httpClient: httpClient,
+	return NewClient(apikey, WithHTTPClient(httpClient))
+}
+
+// NewClient creates new Sensibo instance configured with options.
+//
+// apiKey is the API key that you got from https://home.sensibo.com/me/api
+//
+// Without options the client uses http.DefaultClient and DefaultBaseURL.
+// apiKey is ignored when WithCredentials is used.
+func NewClient(apiKey string, opts ...Option) *Sensibo {
+	s := &Sensibo{
+		credentials: StaticCredentials(apiKey),
+		httpClient:  http.DefaultClient,
+		baseURL:     DefaultBaseURL,
+
+		maxResponseSize: DefaultMaxResponseSize,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 func (s *Sensibo) getRequestURL(
@@ -46,7 +84,7 @@ func (s *Sensibo) getRequestURL(
 	endpoint string,
 	params map[string]string,
 ) string {
-	baseURL := fmt.Sprintf("https://home.sensibo.com/api/%s/%s?apiKey=%s", version, endpoint, s.APIKey)
+	baseURL := fmt.Sprintf("%s/%s/%s", s.baseURL, version, endpoint)
 	queryKeys := []string{}
 	queryParams := ""
 
@@ -60,113 +98,315 @@ func (s *Sensibo) getRequestURL(
 		queryParams = fmt.Sprintf("%s&%s=%s", queryParams, k, url.QueryEscape(params[k]))
 	}
 
-	return fmt.Sprintf("%s%s", baseURL, queryParams)
+	if queryParams == "" {
+		return baseURL
+	}
+
+	return fmt.Sprintf("%s?%s", baseURL, queryParams[1:])
+}
+
+// operation identifies the public method that makes a request.
+type operation struct {
+	name     string
+	deviceID string
 }
 
-func (s *Sensibo) makeRequest(ctx context.Context, method string, url string, body io.Reader) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+// request describes a single call to the Sensibo API.
+type request struct {
+	op       operation
+	method   string
+	version  string
+	endpoint string
+	params   map[string]string
+	body     io.Reader
+	// result is decoded from the result of the response envelope, can be nil.
+	result interface{}
+	// required makes a missing result an error.
+	required bool
+}
+
+func (s *Sensibo) makeRequest(ctx context.Context, r request) error {
+	if _, ok := ctx.Deadline(); !ok && s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+
+		defer cancel()
+	}
+
+	var payload []byte
+
+	if r.body != nil {
+		var err error
+
+		if payload, err = ioutil.ReadAll(r.body); err != nil {
+			return fmt.Errorf("failed to read request body: \n\t%w", err)
+		}
+	}
+
+	return s.makeCachedRequest(ctx, r, payload)
+}
+
+// makeAttempts makes the request, retrying it according to the retry policy.
+func (s *Sensibo) makeAttempts(ctx context.Context, r request, payload []byte) error {
+	for attempt := 1; ; attempt++ {
+		err := s.doGuardedRequest(ctx, r, attempt, payload)
+
+		if err == nil || !s.retryPolicy.shouldRetry(r.method, attempt, err) {
+			return err
+		}
+
+		delay := s.retryPolicy.backoff(attempt, err)
+
+		if s.retryPolicy.OnRetry != nil {
+			s.retryPolicy.OnRetry(RetryEvent{
+				Operation: r.op.name,
+				DeviceID:  r.op.deviceID,
+				Method:    r.method,
+				Version:   r.version,
+				Endpoint:  r.endpoint,
+				Attempt:   attempt,
+				Delay:     delay,
+				Err:       err,
+			})
+		}
+
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// doRequest makes a single attempt of the request.
+func (s *Sensibo) doRequest(ctx context.Context, r request, attempt int, payload []byte) (err error) {
+	info := RequestInfo{
+		Operation: r.op.name,
+		DeviceID:  r.op.deviceID,
+		Method:    r.method,
+		Version:   r.version,
+		Endpoint:  r.endpoint,
+		Attempt:   attempt,
+	}
+	start := time.Now()
+	statusCode := 0
+
+	defer func() {
+		s.observe(ctx, RequestEvent{
+			RequestInfo: info,
+			StatusCode:  statusCode,
+			Latency:     time.Since(start),
+			Err:         err,
+		})
+	}()
+
+	var body io.Reader
+
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	apiKey, err := s.credentials.APIKey(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed getting API key: \n\t%w", err)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		r.method,
+		s.getRequestURL(r.version, r.endpoint, r.params),
+		body,
+	)
 
 	if err != nil {
-		return "", fmt.Errorf("unable to create new request: \n\t%v", err)
+		return fmt.Errorf("unable to create new request: \n\t%w", redactError(err, apiKey))
 	}
 
 	req.Header.Set("Content-type", "application/json")
 	req.Header.Set("accept", "*/*")
 
-	res, err := s.httpClient.Do(req)
+	for k, v := range s.headers {
+		req.Header[k] = v
+	}
 
-	if err != nil || res.StatusCode != http.StatusOK {
-		resBytes, ioErr := ioutil.ReadAll(res.Body)
+	if s.userAgent != "" {
+		req.Header.Set("User-Agent", s.userAgent)
+	}
 
-		if ioErr != nil {
-			return "", fmt.Errorf("io error occurred: \n\t%v", ioErr)
+	if s.rateLimiter != nil {
+		if err := s.rateLimiter.Wait(ctx, r.method, r.endpoint); err != nil {
+			return fmt.Errorf("rate limiter: \n\t%w", err)
 		}
+	}
+
+	res, err := s.send(apiKey, info, req)
 
-		defer res.Body.Close()
+	if err != nil {
+		if res != nil {
+			res.Body.Close()
+		}
 
-		return "", fmt.Errorf(
-			"failed making request \n\tCode: %v \n\tMsg: %v \n\tErr: %v",
-			res.StatusCode,
-			string(resBytes),
-			err,
-		)
+		return &APIError{
+			Method:   r.method,
+			Endpoint: r.endpoint,
+			Version:  r.version,
+			Err:      redactError(err, apiKey),
+		}
 	}
 
 	defer res.Body.Close()
 
-	resBytes, err := ioutil.ReadAll(res.Body)
+	statusCode = res.StatusCode
 
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: \n\t%v", err)
+	resBody := &limitedReader{r: res.Body, max: s.maxResponseSize}
+
+	if res.StatusCode != http.StatusOK {
+		resBytes, _ := ioutil.ReadAll(resBody)
+
+		return &APIError{
+			Method:     r.method,
+			Endpoint:   r.endpoint,
+			Version:    r.version,
+			StatusCode: res.StatusCode,
+			Response:   redactErrorResponse(parseErrorResponse(resBytes), apiKey),
+			Body:       redact(string(resBytes), apiKey),
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+		}
 	}
 
-	return string(resBytes), nil
+	if err := decodeEnvelope(resBody, r.result, r.required); err != nil {
+		if resBody.err != nil {
+			return &APIError{
+				Method:     r.method,
+				Endpoint:   r.endpoint,
+				Version:    r.version,
+				StatusCode: res.StatusCode,
+				Err:        redactError(resBody.err, apiKey),
+			}
+		}
+
+		return redactEnvelopeError(err, apiKey)
+	}
+
+	return nil
 }
 
 func (s *Sensibo) makeGetRequest(
 	ctx context.Context,
+	op operation,
 	version string,
 	endpoint string,
 	params map[string]string,
-) (string, error) {
-	return s.makeRequest(ctx,
-		http.MethodGet,
-		s.getRequestURL(version, endpoint, params),
-		nil,
-	)
+	result interface{},
+) error {
+	return s.makeRequest(ctx, request{
+		op:       op,
+		method:   http.MethodGet,
+		version:  version,
+		endpoint: endpoint,
+		params:   params,
+		result:   result,
+		required: true,
+	})
 }
 
 func (s *Sensibo) makePutRequest(
 	ctx context.Context,
+	op operation,
 	version string,
 	endpoint string,
 	body io.Reader,
-) (string, error) {
-	return s.makeRequest(
-		ctx,
-		http.MethodPut,
-		s.getRequestURL(version, endpoint, map[string]string{}),
-		body,
-	)
+	result interface{},
+) error {
+	return s.makeRequest(ctx, request{
+		op:       op,
+		method:   http.MethodPut,
+		version:  version,
+		endpoint: endpoint,
+		body:     body,
+		result:   result,
+	})
 }
 
 func (s *Sensibo) makePatchRequest(
 	ctx context.Context,
+	op operation,
 	version string,
 	endpoint string,
 	body io.Reader,
-) (string, error) {
-	return s.makeRequest(
-		ctx,
-		http.MethodPatch,
-		s.getRequestURL(version, endpoint, map[string]string{}),
-		body,
-	)
+	result interface{},
+) error {
+	return s.makeRequest(ctx, request{
+		op:       op,
+		method:   http.MethodPatch,
+		version:  version,
+		endpoint: endpoint,
+		body:     body,
+		result:   result,
+	})
 }
 
 func (s *Sensibo) makePostRequest(
 	ctx context.Context,
+	op operation,
 	version string,
 	endpoint string,
 	body io.Reader,
-) (string, error) {
-	return s.makeRequest(
-		ctx,
-		http.MethodPost,
-		s.getRequestURL(version, endpoint, map[string]string{}),
-		body,
-	)
+	result interface{},
+) error {
+	return s.makeRequest(ctx, request{
+		op:       op,
+		method:   http.MethodPost,
+		version:  version,
+		endpoint: endpoint,
+		body:     body,
+		result:   result,
+	})
 }
 
 func (s *Sensibo) makeDeleteRequest(
 	ctx context.Context,
+	op operation,
 	version string,
 	endpoint string,
-) (string, error) {
-	return s.makeRequest(
-		ctx,
-		http.MethodDelete,
-		s.getRequestURL(version, endpoint, map[string]string{}),
-		nil,
-	)
+	result interface{},
+) error {
+	return s.makeRequest(ctx, request{
+		op:       op,
+		method:   http.MethodDelete,
+		version:  version,
+		endpoint: endpoint,
+		result:   result,
+	})
+}
+
+// limitedReader reads at most max bytes and records read errors.
+type limitedReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+	// err is the first error returned by r other than io.EOF.
+	err error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.read > l.max {
+		return 0, ErrResponseTooLarge
+	}
+
+	if remaining := l.max + 1 - l.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+
+	if l.read > l.max {
+		return n, ErrResponseTooLarge
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) && l.err == nil {
+		l.err = err
+	}
+
+	return n, err
 }
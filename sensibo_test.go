@@ -17,6 +17,14 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -29,8 +37,11 @@ func TestNew(t *testing.T) {
 			apikey:     "api-key",
 			httpClient: http.DefaultClient,
 			want: &Sensibo{
-				APIKey:     "api-key",
-				httpClient: http.DefaultClient,
+				credentials: StaticCredentials("api-key"),
+				httpClient:  http.DefaultClient,
+				baseURL:     DefaultBaseURL,
+
+				maxResponseSize: DefaultMaxResponseSize,
 			},
 		},
 	}
@@ -55,13 +66,13 @@ func TestSensibo_getRequestURL(t *testing.T) {
 		want string
 	}{
 		{
-			name: "get correct url with api key appended",
+			name: "get correct url without query params",
 			args: args{
 				version:  "v1",
 				endpoint: "end/point",
 				params:   map[string]string{},
 			},
-			want: "https://home.sensibo.com/api/v1/end/point?apiKey=api-key",
+			want: "https://home.sensibo.com/api/v1/end/point",
 		},
 		{
 			name: "appends query params correctly",
@@ -73,7 +84,7 @@ func TestSensibo_getRequestURL(t *testing.T) {
 					"c": "d",
 				},
 			},
-			want: "https://home.sensibo.com/api/v1/end/point?apiKey=api-key&a=b&c=d",
+			want: "https://home.sensibo.com/api/v1/end/point?a=b&c=d",
 		},
 		{
 			name: "url escape query params",
@@ -85,15 +96,13 @@ func TestSensibo_getRequestURL(t *testing.T) {
 					"c": "d==#",
 				},
 			},
-			want: "https://home.sensibo.com/api/v1/end/point?apiKey=api-key&a=b&c=d%3D%3D%23",
+			want: "https://home.sensibo.com/api/v1/end/point?a=b&c=d%3D%3D%23",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := Sensibo{
-				APIKey: "api-key",
-			}
+			s := NewClient("api-key")
 
 			got := s.getRequestURL(tt.args.version, tt.args.endpoint, tt.args.params)
 
@@ -104,55 +113,43 @@ func TestSensibo_getRequestURL(t *testing.T) {
 
 func TestSensibo_makeRequest(t *testing.T) {
 	type args struct {
-		ctx    context.Context
-		method string
-		url    string
-		body   io.Reader
+		ctx context.Context
+		req request
 	}
 	tests := []struct {
 		name   string
 		args   args
 		DoMock func(req *http.Request) (*http.Response, error)
 		want   string
-		err    error
+		err    string
 	}{
 		{
 			name: "fails on bad method",
 			args: args{
-				ctx:    context.Background(),
-				method: "bad method",
-				url:    "https://sensibo.com",
-				body:   nil,
+				ctx: context.Background(),
+				req: request{method: "bad method", version: "v1", endpoint: "end/point"},
 			},
 			DoMock: http.DefaultClient.Do,
 			want:   "",
-			err:    fmt.Errorf("unable to create new request: \n\tnet/http: invalid method \"bad method\""),
+			err:    "unable to create new request: \n\tnet/http: invalid method \"bad method\"",
 		},
 		{
 			name: "fails if http.DefaultClient.Do returns an error",
 			args: args{
-				ctx:    context.Background(),
-				method: http.MethodGet,
-				url:    "https://sensibo.com",
-				body:   nil,
+				ctx: context.Background(),
+				req: request{method: http.MethodGet, version: "v1", endpoint: "end/point"},
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
-				return &http.Response{
-						Body:       ioutil.NopCloser(strings.NewReader("")),
-						StatusCode: 200,
-					},
-					fmt.Errorf("Error")
+				return nil, fmt.Errorf("Error")
 			},
 			want: "",
-			err:  fmt.Errorf("failed making request \n\tCode: 200 \n\tMsg:  \n\tErr: Error"),
+			err:  "sensibo: GET v1/end/point: Error",
 		},
 		{
 			name: "fails if response has non 200 status code",
 			args: args{
-				ctx:    context.Background(),
-				method: http.MethodGet,
-				url:    "https://sensibo.com",
-				body:   nil,
+				ctx: context.Background(),
+				req: request{method: http.MethodGet, version: "v1", endpoint: "end/point"},
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
@@ -161,45 +158,95 @@ func TestSensibo_makeRequest(t *testing.T) {
 				}, nil
 			},
 			want: "",
-			err:  fmt.Errorf("failed making request \n\tCode: 301 \n\tMsg:  \n\tErr: <nil>"),
+			err:  "sensibo: GET v1/end/point: 301 Moved Permanently",
+		},
+		{
+			name: "includes parsed error body",
+			args: args{
+				ctx: context.Background(),
+				req: request{method: http.MethodGet, version: "v2", endpoint: "pods/1234"},
+			},
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"error","reason":"NotFound","message":"no pod"}`)),
+					StatusCode: 404,
+				}, nil
+			},
+			want: "",
+			err:  "sensibo: GET v2/pods/1234: 404 Not Found: NotFound: no pod",
 		},
 		{
 			name: "successful request",
 			args: args{
-				ctx:    context.Background(),
-				method: http.MethodGet,
-				url:    "https://sensibo.com",
-				body:   nil,
+				ctx: context.Background(),
+				req: request{method: http.MethodGet, version: "v1", endpoint: "end/point"},
 			},
 			DoMock: func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
-					Body:       ioutil.NopCloser(strings.NewReader("Success")),
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":"Success"}`)),
 					StatusCode: 200,
 				}, nil
 			},
 			want: "Success",
-			err:  nil,
+			err:  "",
+		},
+		{
+			name: "fails if response is too large",
+			args: args{
+				ctx: context.Background(),
+				req: request{method: http.MethodGet, version: "v1", endpoint: "end/point"},
+			},
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":"` + strings.Repeat("a", 100) + `"}`)),
+					StatusCode: 200,
+				}, nil
+			},
+			want: "",
+			err:  "sensibo: failed decoding response: sensibo: response too large",
+		},
+		{
+			name: "fails with api error if reading the body fails",
+			args: args{
+				ctx: context.Background(),
+				req: request{method: http.MethodGet, version: "v1", endpoint: "end/point"},
+			},
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body: ioutil.NopCloser(io.MultiReader(
+						strings.NewReader(`{"status":"succ`),
+						errReader{err: fmt.Errorf("connection reset")},
+					)),
+					StatusCode: 200,
+				}, nil
+			},
+			want: "",
+			err:  "sensibo: GET v1/end/point: connection reset",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := New(
-				&mocks.HTTPClientMock{
-					DoMock: tt.DoMock,
-				},
+			s := NewClient(
 				"api-key",
+				WithHTTPClient(&mocks.HTTPClientMock{
+					DoMock: tt.DoMock,
+				}),
+				WithMaxResponseSize(64),
 			)
 
-			got, err := s.makeRequest(
-				tt.args.ctx,
-				tt.args.method,
-				tt.args.url,
-				tt.args.body,
-			)
+			got := ""
+			tt.args.req.result = &got
+
+			err := s.makeRequest(tt.args.ctx, tt.args.req)
 
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
 		})
 	}
 }
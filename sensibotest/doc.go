@@ -0,0 +1,25 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package sensibotest provides an in-memory Sensibo API for tests.
+//
+// Server emulates the endpoints used by the sensibo client with stateful
+// pods that can be seeded from fixtures, so end-to-end tests can run offline:
+//
+//	srv := sensibotest.NewServer(sensibotest.WithPods(sensibotest.Pod{
+//		Device: models.Device{ID: "1234"},
+//	}))
+//	defer srv.Close()
+//
+//	client := srv.NewClient()
+//	state, err := client.SetDeviceACState(ctx, "1234", models.ACStateData{On: true})
+//
+// Failures such as latency, rate limiting, 5xx bursts, broken bodies and
+// connection resets can be injected per endpoint with InjectFault.
+//
+// Pods with a Simulation have measurements that follow their AC state
+// through a room thermal model, use WithClock to fast-forward time.
+//
+// Recorder records real API traffic to cassette files and replays it.
+package sensibotest
@@ -0,0 +1,230 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault is a failure injected into the responses of matching requests.
+//
+// Latency is applied first, then at most one of Reset, StatusCode,
+// MalformedJSON and Truncate, in this order.
+type Fault struct {
+	// Method matches the request method, empty matches all methods.
+	Method string
+	// Endpoint is matched against the endpoint without the API version
+	// using path.Match, e.g: "pods/*/acStates". Empty matches all endpoints.
+	Endpoint string
+	// Times is the number of matching requests the fault applies to,
+	// 0 applies it to all of them.
+	Times int
+	// Latency delays the response.
+	Latency time.Duration
+	// Reset closes the connection without sending a response.
+	Reset bool
+	// StatusCode responds with this status code and a Sensibo error body.
+	StatusCode int
+	// RetryAfter sets the Retry-After header of StatusCode responses.
+	RetryAfter time.Duration
+	// MalformedJSON responds with status 200 and a body that is not valid JSON.
+	MalformedJSON bool
+	// Truncate cuts the body of the response in half while keeping
+	// the Content-Length of the full body.
+	Truncate bool
+}
+
+// RecordedRequest is a request received by the server.
+type RecordedRequest struct {
+	Method string
+	// Endpoint is the path after "/api/", e.g: "v2/pods/1234".
+	Endpoint string
+	Query    string
+}
+
+type activeFault struct {
+	Fault
+	remaining int
+}
+
+// InjectFault adds a fault, faults are matched in the order they were added
+// and only the first matching one applies to a request.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &activeFault{Fault: f, remaining: f.Times})
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the requests received by the server in order.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]RecordedRequest{}, s.requests...)
+}
+
+// RequestCount returns the number of received requests matching method and
+// endpoint, which are matched like Fault.Method and Fault.Endpoint.
+func (s *Server) RequestCount(method string, endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+
+	for _, r := range s.requests {
+		if matchEndpoint(method, endpoint, r.Method, r.Endpoint) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// record logs the request and returns the fault that applies to it, if any.
+func (s *Server) record(r *http.Request, endpoint string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, RecordedRequest{
+		Method:   r.Method,
+		Endpoint: endpoint,
+		Query:    redactQuery(r.URL.RawQuery),
+	})
+
+	for i, f := range s.faults {
+		if !matchEndpoint(f.Method, f.Endpoint, r.Method, endpoint) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.remaining--
+
+			if f.remaining <= 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		fault := f.Fault
+
+		return &fault
+	}
+
+	return nil
+}
+
+// serveFault writes the response of a fault, requests that the fault
+// lets through are handled by next.
+func serveFault(w http.ResponseWriter, r *http.Request, f *Fault, next http.HandlerFunc) {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+	}
+
+	switch {
+	case f.Reset:
+		resetConnection(w)
+	case f.StatusCode != 0:
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.RetryAfter.Seconds()))))
+		}
+
+		writeError(w, f.StatusCode, http.StatusText(f.StatusCode), "injected fault")
+	case f.MalformedJSON:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write([]byte(`{"status":"success","result":{"id":`))
+	case f.Truncate:
+		rec := httptest.NewRecorder()
+		next(rec, r)
+
+		body := rec.Body.Bytes()
+
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(rec.Code)
+
+		_, _ = w.Write(body[:len(body)/2])
+	default:
+		next(w, r)
+	}
+}
+
+// resetConnection closes the underlying connection of w without a response.
+func resetConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+
+	conn, _, err := hijacker.Hijack()
+
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+
+	_ = conn.Close()
+}
+
+func matchEndpoint(method string, pattern string, reqMethod string, endpoint string) bool {
+	if method != "" && method != reqMethod {
+		return false
+	}
+
+	if pattern == "" {
+		return true
+	}
+
+	// the pattern does not include the API version
+	if i := strings.Index(endpoint, "/"); i >= 0 {
+		endpoint = endpoint[i+1:]
+	}
+
+	ok, _ := path.Match(pattern, endpoint)
+
+	return ok
+}
+
+func redactQuery(query string) string {
+	parts := strings.Split(query, "&")
+
+	for i, p := range parts {
+		if strings.HasPrefix(p, "apiKey=") {
+			parts[i] = "apiKey=REDACTED"
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
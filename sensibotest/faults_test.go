@@ -0,0 +1,134 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sensibo "github.com/odinn1984/go-sensibo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryClient(srv *Server) *sensibo.Sensibo {
+	return srv.NewClient(sensibo.WithRetryPolicy(sensibo.RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Millisecond,
+	}))
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    Fault
+		attempts int
+		err      error
+		errMsg   string
+	}{
+		{
+			name:     "server error burst is retried",
+			fault:    Fault{Endpoint: "pods/*", StatusCode: http.StatusServiceUnavailable, Times: 2},
+			attempts: 3,
+		},
+		{
+			name:     "persistent server errors",
+			fault:    Fault{Endpoint: "pods/*", StatusCode: http.StatusInternalServerError},
+			attempts: 4,
+			err:      sensibo.ErrServer,
+		},
+		{
+			name:     "rate limited with retry after",
+			fault:    Fault{Method: http.MethodGet, StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second, Times: 1},
+			attempts: 2,
+		},
+		{
+			name:     "connection reset is retried",
+			fault:    Fault{Reset: true, Times: 1},
+			attempts: 2,
+		},
+		{
+			name:     "malformed json is not retried",
+			fault:    Fault{MalformedJSON: true},
+			attempts: 1,
+			errMsg:   "failed decoding response",
+		},
+		{
+			name:     "truncated body is retried",
+			fault:    Fault{Truncate: true, Times: 1},
+			attempts: 2,
+		},
+		{
+			name:     "other endpoints are not affected",
+			fault:    Fault{Endpoint: "pods/*/acStates", StatusCode: http.StatusInternalServerError},
+			attempts: 1,
+		},
+		{
+			name:     "other methods are not affected",
+			fault:    Fault{Method: http.MethodPost, StatusCode: http.StatusInternalServerError},
+			attempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFixtureServer(t)
+			srv.InjectFault(tt.fault)
+
+			device, err := newRetryClient(srv).GetDevice(context.Background(), "abc123", []string{"*"})
+
+			switch {
+			case tt.err != nil:
+				assert.True(t, errors.Is(err, tt.err), "got %v", err)
+			case tt.errMsg != "":
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, "abc123", device.ID)
+			}
+
+			assert.Equal(t, tt.attempts, srv.RequestCount(http.MethodGet, "pods/*"))
+		})
+	}
+}
+
+func TestServer_InjectFault_latency(t *testing.T) {
+	srv := newFixtureServer(t)
+	srv.InjectFault(Fault{Endpoint: "pods/abc123", Latency: time.Second})
+
+	client := srv.NewClient(sensibo.WithDefaultTimeout(50 * time.Millisecond))
+	start := time.Now()
+
+	_, err := client.GetDevice(context.Background(), "abc123", []string{"*"})
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	_, err = client.GetDevice(context.Background(), "def456", []string{"*"})
+
+	assert.NoError(t, err)
+
+	srv.ClearFaults()
+
+	_, err = client.GetDevice(context.Background(), "abc123", []string{"*"})
+
+	assert.NoError(t, err)
+}
+
+func TestServer_Requests(t *testing.T) {
+	srv := newFixtureServer(t)
+
+	_, err := srv.NewClient().GetDeviceACStates(context.Background(), "abc123", 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []RecordedRequest{{
+		Method:   http.MethodGet,
+		Endpoint: "v2/pods/abc123/acStates",
+		Query:    "apiKey=REDACTED&limit=5",
+	}}, srv.Requests())
+}
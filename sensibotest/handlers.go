@@ -0,0 +1,553 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+func (s *Server) apiRoutes() []route {
+	return []route{
+		{http.MethodGet, "v2/users/me/pods", s.getPods},
+		{http.MethodGet, "v2/pods/{id}", s.getPod},
+		{http.MethodGet, "v2/pods/{id}/acStates", s.getACStates},
+		{http.MethodPost, "v2/pods/{id}/acStates", s.postACState},
+		{http.MethodPatch, "v2/pods/{id}/acStates/{property}", s.patchACState},
+		{http.MethodGet, "v2/pods/{id}/historicalMeasurements", s.getHistoricalMeasurements},
+		{http.MethodGet, "v2/pods/{id}/smartmode", s.getSmartMode},
+		{http.MethodPut, "v2/pods/{id}/smartmode", s.putSmartMode},
+		{http.MethodGet, "v1/pods/{id}/timer", s.getTimer},
+		{http.MethodPut, "v1/pods/{id}/timer", s.putTimer},
+		{http.MethodDelete, "v1/pods/{id}/timer", s.deleteTimer},
+		{http.MethodGet, "v1/pods/{id}/schedules", s.getSchedules},
+		{http.MethodPost, "v1/pods/{id}/schedules", s.postSchedule},
+		{http.MethodGet, "v1/pods/{id}/schedules/{scheduleID}", s.getSchedule},
+		{http.MethodPut, "v1/pods/{id}/schedules/{scheduleID}", s.putSchedule},
+		{http.MethodDelete, "v1/pods/{id}/schedules/{scheduleID}", s.deleteSchedule},
+	}
+}
+
+func (s *Server) getPods(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := r.URL.Query().Get("fields")
+	devices := []interface{}{}
+
+	for _, id := range s.order {
+		devices = append(devices, filterFields(s.deviceView(s.pods[id]), fields))
+	}
+
+	writeResult(w, devices)
+}
+
+func (s *Server) getPod(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	writeResult(w, filterFields(s.deviceView(p), r.URL.Query().Get("fields")))
+}
+
+func (s *Server) getACStates(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	states := append([]models.ACState{}, p.ACStates...)
+
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 && limit < len(states) {
+		states = states[:limit]
+	}
+
+	for i := range states {
+		states[i].ACState.Timestamp.SecondsAgo = s.secondsAgo(states[i].ACState.Timestamp.Time)
+	}
+
+	writeResult(w, states)
+}
+
+func (s *Server) postACState(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	payload := struct {
+		ACState models.ACStatePatch `json:"acState"`
+	}{}
+
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+
+	state := payload.ACState.Apply(p.Device.ACState.ACStateData)
+
+	writeResult(w, s.setACState(p, state, "UserRequest", s.now()))
+}
+
+func (s *Server) patchACState(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	payload := struct {
+		NewValue json.RawMessage `json:"newValue"`
+	}{}
+
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+
+	state, err := p.Device.ACState.WithProperty(params["property"], rawString(payload.NewValue))
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidValue", err.Error())
+
+		return
+	}
+
+	writeResult(w, s.setACState(p, state, "UserRequest", s.now()))
+}
+
+func (s *Server) getHistoricalMeasurements(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+
+	if err != nil || days < 1 {
+		days = 1
+	}
+
+	since := s.now().Add(-time.Duration(days) * 24 * time.Hour)
+	result := models.HistoricalMeasurements{}
+
+	for _, m := range p.HistoricalMeasurements.Temperature {
+		if s.after(m.Time, since) {
+			result.Temperature = append(result.Temperature, m)
+		}
+	}
+
+	for _, m := range p.HistoricalMeasurements.Humidity {
+		if s.after(m.Time, since) {
+			result.Humidity = append(result.Humidity, m)
+		}
+	}
+
+	writeResult(w, result)
+}
+
+func (s *Server) getSmartMode(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	writeResult(w, p.Device.SmartMode)
+}
+
+func (s *Server) putSmartMode(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	payload := struct {
+		Enabled bool `json:"enabled"`
+	}{}
+
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+
+	p.Device.SmartMode.Enabled = payload.Enabled
+
+	if p.Device.SmartMode.DeviceUID == "" {
+		p.Device.SmartMode.DeviceUID = p.Device.ID
+	}
+
+	writeResult(w, p.Device.SmartMode)
+}
+
+func (s *Server) getTimer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	writeResult(w, s.timerView(p.Timer))
+}
+
+func (s *Server) putTimer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	payload := struct {
+		MinutesFromNow int                `json:"minutesFromNow"`
+		ACState        models.ACStateData `json:"acState"`
+	}{}
+
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+
+	if payload.MinutesFromNow < 1 {
+		writeError(w, http.StatusBadRequest, "InvalidValue", "minutesFromNow must be positive")
+
+		return
+	}
+
+	now := s.now()
+	p.Timer = &models.DeviceTimer{
+		ID:         s.newID(),
+		IsEnabled:  true,
+		ACState:    payload.ACState,
+		CreateTime: models.NewTimestamp(now),
+		TargetTime: models.NewTimestamp(now.Add(time.Duration(payload.MinutesFromNow) * time.Minute)),
+	}
+
+	writeResult(w, s.timerView(p.Timer))
+}
+
+func (s *Server) deleteTimer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	p.Timer = nil
+
+	writeResult(w, nil)
+}
+
+func (s *Server) getSchedules(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	writeResult(w, append([]models.DeviceSchedule{}, p.Schedules...))
+}
+
+func (s *Server) postSchedule(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findPod(w, params["id"])
+
+	if !ok {
+		return
+	}
+
+	payload := models.CreateDeviceSchedulePayload{}
+
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+
+	schedule := models.DeviceSchedule{
+		ID:              s.newID(),
+		IsEnabled:       true,
+		CreateTime:      models.NewTimestamp(s.now()),
+		RecurringDays:   payload.RecurringDays,
+		TargetTimeLocal: payload.TargetTimeLocal,
+		TimeZone:        payload.TimeZone,
+		PodUID:          p.Device.ID,
+	}
+	schedule.ACState.ACStateData = payload.ACState
+	p.Schedules = append(p.Schedules, schedule)
+
+	writeResult(w, schedule)
+}
+
+func (s *Server) getSchedule(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.findSchedule(w, params["id"], params["scheduleID"])
+
+	if !ok {
+		return
+	}
+
+	writeResult(w, schedule)
+}
+
+func (s *Server) putSchedule(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.findSchedule(w, params["id"], params["scheduleID"])
+
+	if !ok {
+		return
+	}
+
+	payload := struct {
+		IsEnabled bool `json:"isEnabled"`
+	}{}
+
+	if !decodeBody(w, r, &payload) {
+		return
+	}
+
+	schedule.IsEnabled = payload.IsEnabled
+
+	writeResult(w, schedule)
+}
+
+func (s *Server) deleteSchedule(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.findSchedule(w, params["id"], params["scheduleID"]); !ok {
+		return
+	}
+
+	p := s.pods[params["id"]]
+
+	for i := range p.Schedules {
+		if p.Schedules[i].ID == params["scheduleID"] {
+			p.Schedules = append(p.Schedules[:i], p.Schedules[i+1:]...)
+
+			break
+		}
+	}
+
+	writeResult(w, nil)
+}
+
+// findPod returns the pod with id, it writes a 404 response if there is none.
+func (s *Server) findPod(w http.ResponseWriter, id string) (*Pod, bool) {
+	p, ok := s.pods[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("pod %s not found", id))
+	}
+
+	return p, ok
+}
+
+// findSchedule returns the schedule of a pod, it writes a 404 response if there is none.
+func (s *Server) findSchedule(w http.ResponseWriter, id string, scheduleID string) (*models.DeviceSchedule, bool) {
+	p, ok := s.findPod(w, id)
+
+	if !ok {
+		return nil, false
+	}
+
+	for i := range p.Schedules {
+		if p.Schedules[i].ID == scheduleID {
+			return &p.Schedules[i], true
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("schedule %s not found", scheduleID))
+
+	return nil, false
+}
+
+// setACState applies state to the pod and records it in the AC state history.
+func (s *Server) setACState(p *Pod, state models.ACStateData, reason string, at time.Time) models.ACState {
+	record := models.ACState{
+		ID:                s.newID(),
+		Status:            "Success",
+		ChangedProperties: changedProperties(p.Device.ACState.ACStateData, state),
+		Reason:            reason,
+	}
+	record.ACState.Timestamp.Time = models.NewTimestamp(at)
+	record.ACState.ACStateData = state
+
+	p.Device.ACState.ACStateData = state
+	p.Device.ACState.Timestamp = record.ACState.Timestamp
+	p.ACStates = append([]models.ACState{record}, p.ACStates...)
+
+	return record
+}
+
+// deviceView returns the device of a pod as returned by the API.
+func (s *Server) deviceView(p *Pod) models.Device {
+	device := p.Device
+	device.Schedules = append([]models.DeviceSchedule{}, p.Schedules...)
+	device.ACState.Timestamp.SecondsAgo = s.secondsAgo(device.ACState.Timestamp.Time)
+	device.Measurements.Time.SecondsAgo = s.secondsAgo(device.Measurements.Time.Time)
+
+	if t := s.timerView(p.Timer); t != nil {
+		device.Timer = *t
+	}
+
+	return device
+}
+
+func (s *Server) timerView(timer *models.DeviceTimer) *models.DeviceTimer {
+	if timer == nil {
+		return nil
+	}
+
+	t := *timer
+	t.CreateTimeSecondsAgo = int(s.secondsAgo(t.CreateTime))
+	t.TargetTimeSecondsFromNow = -int(s.secondsAgo(t.TargetTime))
+
+	return &t
+}
+
+// after reports whether t is after since, unknown times are always after.
+func (s *Server) after(t models.Timestamp, since time.Time) bool {
+	return t.IsZero() || t.After(since)
+}
+
+func changedProperties(old models.ACStateData, state models.ACStateData) []string {
+	changed := []string{}
+
+	if old.On != state.On {
+		changed = append(changed, "on")
+	}
+
+	if old.Mode != state.Mode {
+		changed = append(changed, "mode")
+	}
+
+	if old.FanLevel != state.FanLevel {
+		changed = append(changed, "fanLevel")
+	}
+
+	if old.TargetTemperature != state.TargetTemperature {
+		changed = append(changed, "targetTemperature")
+	}
+
+	if old.TemperatureUnit != state.TemperatureUnit {
+		changed = append(changed, "temperatureUnit")
+	}
+
+	if old.Swing != state.Swing {
+		changed = append(changed, "swing")
+	}
+
+	if old.HorizontalSwing != state.HorizontalSwing {
+		changed = append(changed, "horizontalSwing")
+	}
+
+	if old.Light != state.Light {
+		changed = append(changed, "light")
+	}
+
+	return changed
+}
+
+// filterFields keeps only the comma separated fields of v,
+// an empty list or "*" keeps all of them.
+func filterFields(v interface{}, fields string) interface{} {
+	if fields == "" || strings.Contains(fields, "*") {
+		return v
+	}
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return v
+	}
+
+	all := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &all); err != nil {
+		return v
+	}
+
+	wanted := map[string]bool{}
+
+	for _, f := range strings.Split(fields, ",") {
+		wanted[strings.ToLower(strings.TrimSpace(f))] = true
+	}
+
+	filtered := map[string]json.RawMessage{}
+
+	for k, v := range all {
+		if wanted[strings.ToLower(k)] {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
+
+// rawString returns the value of a JSON string, or the raw JSON of other values.
+func rawString(raw json.RawMessage) string {
+	str := ""
+
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+
+	return string(raw)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidJSON", err.Error())
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,334 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	sensibo "github.com/odinn1984/go-sensibo"
+)
+
+// ErrNoInteraction is returned by a strict Recorder for requests
+// that match no recorded interaction.
+var ErrNoInteraction = errors.New("sensibotest: no matching interaction")
+
+// RecorderMode selects whether a Recorder records or replays traffic.
+type RecorderMode int
+
+// Recorder modes.
+const (
+	// ModeReplay answers requests from the cassette.
+	ModeReplay RecorderMode = iota
+	// ModeRecord sends requests and adds them to the cassette.
+	ModeRecord
+)
+
+// RecorderOptions configures a Recorder.
+type RecorderOptions struct {
+	Mode RecorderMode
+	// Client sends the requests that are recorded, http.DefaultClient by default.
+	Client sensibo.HTTPClient
+	// Strict makes replay fail with ErrNoInteraction for requests that
+	// match no interaction, otherwise they are sent with Client and recorded.
+	Strict bool
+	// Secrets are extra values scrubbed from cassettes. The API key of
+	// every request is always scrubbed.
+	Secrets []string
+}
+
+// Cassette holds recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedHTTPRequest  `json:"request"`
+	Response RecordedHTTPResponse `json:"response"`
+}
+
+// RecordedHTTPRequest is the part of a request that is matched on replay.
+type RecordedHTTPRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Query is sorted and does not include the API key.
+	Query string `json:"query"`
+	Body  string `json:"body,omitempty"`
+}
+
+// RecordedHTTPResponse is a recorded response.
+type RecordedHTTPResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is a sensibo.HTTPClient that records traffic to a cassette file
+// and replays it, so tests can run against real API responses offline.
+//
+// Requests are matched on method, path and query, ignoring the API key and
+// the order of query parameters. Interactions with the same request are
+// replayed in the order they were recorded, the last one repeats once
+// they are all played. It is safe for concurrent use.
+type Recorder struct {
+	path    string
+	options RecorderOptions
+
+	mu       sync.Mutex
+	cassette Cassette
+	played   map[int]bool
+}
+
+// NewRecorder creates a recorder for the cassette file at path.
+//
+// The cassette must exist in ModeReplay, it is created by Save in ModeRecord.
+func NewRecorder(path string, options RecorderOptions) (*Recorder, error) {
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+
+	r := &Recorder{
+		path:    path,
+		options: options,
+		played:  map[int]bool{},
+	}
+
+	if options.Mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed reading cassette \n\t%w", err)
+	}
+
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("failed decoding cassette \n\t%w", err)
+	}
+
+	return r, nil
+}
+
+// Do implements sensibo.HTTPClient.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	recorded, secrets, err := r.recordRequest(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if r.options.Mode == ModeReplay {
+		if i, ok := r.match(recorded); ok {
+			return r.cassette.Interactions[i].Response.response(req), nil
+		}
+
+		if r.options.Strict {
+			return nil, fmt.Errorf("%w: %s %s?%s", ErrNoInteraction, recorded.Method, recorded.Path, recorded.Query)
+		}
+	}
+
+	res, err := r.options.Client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := res.Header.Clone()
+	header.Del("Date")
+	header.Del("Set-Cookie")
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedHTTPResponse{
+			StatusCode: res.StatusCode,
+			Header:     header,
+			Body:       scrub(string(body), secrets),
+		},
+	})
+	r.played[len(r.cassette.Interactions)-1] = true
+	r.mu.Unlock()
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return res, nil
+}
+
+// Save writes the cassette to its file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed encoding cassette \n\t%w", err)
+	}
+
+	if err := ioutil.WriteFile(r.path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed writing cassette \n\t%w", err)
+	}
+
+	return nil
+}
+
+// Cassette returns a copy of the recorded interactions.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Cassette{Interactions: append([]Interaction{}, r.cassette.Interactions...)}
+}
+
+// Unplayed returns the interactions of the cassette that were not replayed.
+func (r *Recorder) Unplayed() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unplayed := []Interaction{}
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.played[i] {
+			unplayed = append(unplayed, interaction)
+		}
+	}
+
+	return unplayed
+}
+
+// match returns the index of the interaction to replay for req.
+func (r *Recorder) match(req RecordedHTTPRequest) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+
+	for i, interaction := range r.cassette.Interactions {
+		candidate := interaction.Request
+
+		if candidate.Method != req.Method || candidate.Path != req.Path || candidate.Query != req.Query {
+			continue
+		}
+
+		if !r.played[i] {
+			r.played[i] = true
+
+			return i, true
+		}
+
+		last = i
+	}
+
+	return last, last >= 0
+}
+
+// recordRequest returns the scrubbed form of req and the secrets it carries.
+func (r *Recorder) recordRequest(req *http.Request) (RecordedHTTPRequest, []string, error) {
+	secrets := append([]string{}, r.options.Secrets...)
+	query := req.URL.Query()
+
+	if key := query.Get("apiKey"); key != "" {
+		secrets = append(secrets, key)
+	}
+
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" {
+		secrets = append(secrets, token)
+	}
+
+	query.Del("apiKey")
+
+	recorded := RecordedHTTPRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  normalizeQuery(query),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+
+		if err != nil {
+			return recorded, nil, fmt.Errorf("failed reading request body \n\t%w", err)
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		recorded.Body = scrub(string(body), secrets)
+	}
+
+	return recorded, secrets, nil
+}
+
+func (res RecordedHTTPResponse) response(req *http.Request) *http.Response {
+	header := res.Header.Clone()
+
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}
+}
+
+// normalizeQuery encodes query with sorted keys and values.
+func normalizeQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+
+	for k := range query {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := []string{}
+
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+
+		for _, v := range values {
+			parts = append(parts, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func scrub(str string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			str = strings.ReplaceAll(str, secret, "REDACTED")
+		}
+	}
+
+	return str
+}
+
+var _ sensibo.HTTPClient = (*Recorder)(nil)
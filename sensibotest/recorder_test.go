@@ -0,0 +1,116 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sensibo "github.com/odinn1984/go-sensibo"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "sensibotest")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	return dir
+}
+
+func TestRecorder_recordAndReplay(t *testing.T) {
+	cassette := filepath.Join(tempDir(t), "cassette.json")
+	srv := newFixtureServer(t, WithAPIKey("super-secret-key"))
+
+	recorder, err := NewRecorder(cassette, RecorderOptions{Mode: ModeRecord, Client: srv.HTTPClient()})
+	assert.NoError(t, err)
+
+	client := sensibo.NewClient(srv.APIKey(), sensibo.WithBaseURL(srv.BaseURL()), sensibo.WithHTTPClient(recorder))
+	ctx := context.Background()
+
+	recordedDevice, err := client.GetDevice(ctx, "abc123", []string{"*"})
+	assert.NoError(t, err)
+
+	_, err = client.SetDeviceACState(ctx, "abc123", models.ACStateData{On: true, Mode: "cool"})
+	assert.NoError(t, err)
+
+	_, err = client.GetDevice(ctx, "abc123", []string{"*"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, recorder.Save())
+	srv.Close()
+
+	data, err := ioutil.ReadFile(cassette)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "super-secret-key")
+	assert.Len(t, recorder.Cassette().Interactions, 3)
+
+	replayer, err := NewRecorder(cassette, RecorderOptions{Mode: ModeReplay, Strict: true})
+	assert.NoError(t, err)
+
+	client = sensibo.NewClient("another-key", sensibo.WithBaseURL(srv.BaseURL()), sensibo.WithHTTPClient(replayer))
+
+	device, err := client.GetDevice(ctx, "abc123", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, recordedDevice, device)
+	assert.False(t, device.ACState.On)
+
+	state, err := client.SetDeviceACState(ctx, "abc123", models.ACStateData{On: true, Mode: "cool"})
+
+	assert.NoError(t, err)
+	assert.True(t, state.ACState.On)
+
+	// identical requests are replayed in the recorded order
+	device, err = client.GetDevice(ctx, "abc123", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.True(t, device.ACState.On)
+	assert.Empty(t, replayer.Unplayed())
+
+	_, err = client.GetDevice(ctx, "def456", []string{"*"})
+
+	assert.True(t, errors.Is(err, ErrNoInteraction))
+	assert.Contains(t, err.Error(), "GET /api/v2/pods/def456?fields=%2A")
+}
+
+func TestRecorder_replayFallsBackWhenNotStrict(t *testing.T) {
+	cassette := filepath.Join(tempDir(t), "cassette.json")
+	assert.NoError(t, ioutil.WriteFile(cassette, []byte(`{"interactions":[]}`), 0o600))
+
+	srv := newFixtureServer(t)
+	recorder, err := NewRecorder(cassette, RecorderOptions{Client: srv.HTTPClient()})
+	assert.NoError(t, err)
+
+	client := sensibo.NewClient(srv.APIKey(), sensibo.WithBaseURL(srv.BaseURL()), sensibo.WithHTTPClient(recorder))
+
+	_, err = client.GetDevice(context.Background(), "abc123", []string{"id"})
+
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Cassette().Interactions, 1)
+	assert.Equal(t, 1, srv.RequestCount("GET", "pods/*"))
+}
+
+func TestRecorder_missingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(tempDir(t), "missing.json"), RecorderOptions{})
+
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "failed reading cassette"))
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	query, _ := url.ParseQuery("b=2&a=3&b=1&fields=id,room")
+
+	assert.Equal(t, "a=3&b=1&b=2&fields=id%2Croom", normalizeQuery(query))
+}
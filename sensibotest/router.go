@@ -0,0 +1,131 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params map[string]string)
+
+type route struct {
+	method  string
+	pattern string
+	handler handlerFunc
+}
+
+// matchPath reports whether path matches pattern, it returns
+// the values of the "{name}" segments of the pattern.
+func matchPath(pattern string, path string) (map[string]string, bool) {
+	patternParts := strings.Split(pattern, "/")
+	pathParts := strings.Split(path, "/")
+
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for i, part := range patternParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if pathParts[i] == "" {
+				return nil, false
+			}
+
+			params[strings.Trim(part, "{}")] = pathParts[i]
+
+			continue
+		}
+
+		if part != pathParts[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f := s.record(r, strings.TrimPrefix(r.URL.Path, "/api/")); f != nil {
+		serveFault(w, r, f, s.serveAPI)
+
+		return
+	}
+
+	s.serveAPI(w, r)
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "invalid API key")
+
+		return
+	}
+
+	s.mu.Lock()
+	s.simulate()
+	s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/")
+	pathFound := false
+
+	for _, rt := range s.routes {
+		params, ok := matchPath(rt.pattern, path)
+
+		if !ok {
+			continue
+		}
+
+		if rt.method != r.Method {
+			pathFound = true
+
+			continue
+		}
+
+		rt.handler(w, r, params)
+
+		return
+	}
+
+	if pathFound {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" is not allowed")
+
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "NotFound", "no such endpoint "+r.URL.Path)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if key := r.URL.Query().Get("apiKey"); key != "" {
+		return key == s.apiKey
+	}
+
+	return r.Header.Get("Authorization") == "Bearer "+s.apiKey
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"result": result,
+	})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, reason string, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"status":  "error",
+		"reason":  reason,
+		"message": message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,234 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	sensibo "github.com/odinn1984/go-sensibo"
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+// DefaultAPIKey is the API key accepted by a Server unless WithAPIKey is used.
+const DefaultAPIKey = "test-api-key"
+
+// Pod is the state of an emulated device.
+type Pod struct {
+	Device models.Device `json:"device"`
+	// ACStates is the AC state history, newest first.
+	ACStates               []models.ACState              `json:"acStates"`
+	HistoricalMeasurements models.HistoricalMeasurements `json:"historicalMeasurements"`
+	Schedules              []models.DeviceSchedule       `json:"schedules"`
+	// Timer is nil when no timer is set.
+	Timer *models.DeviceTimer `json:"timer"`
+	// Simulation makes the measurements of the pod evolve over time,
+	// nil keeps them as they are.
+	Simulation *Simulation `json:"simulation,omitempty"`
+}
+
+// Fixture is the format of the files read by LoadFixtures.
+type Fixture struct {
+	Pods []Pod `json:"pods"`
+}
+
+// Option configures a Server created with NewServer.
+type Option func(*Server)
+
+// WithAPIKey sets the API key that the server accepts.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithPods seeds the server with pods.
+func WithPods(pods ...Pod) Option {
+	return func(s *Server) {
+		for _, p := range pods {
+			s.addPod(p)
+		}
+	}
+}
+
+// Server is an in-memory emulation of the Sensibo API.
+//
+// It serves the API under "/api", see BaseURL. It is safe for concurrent use.
+type Server struct {
+	srv    *httptest.Server
+	apiKey string
+	now    func() time.Time
+	routes []route
+
+	mu       sync.Mutex
+	pods     map[string]*Pod
+	order    []string
+	nextID   int
+	faults   []*activeFault
+	requests []RecordedRequest
+
+	simulations map[string]*simulationState
+}
+
+// NewServer starts a server, Close must be called when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		apiKey: DefaultAPIKey,
+		now:    time.Now,
+		pods:   map[string]*Pod{},
+
+		simulations: map[string]*simulationState{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.routes = s.apiRoutes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL returns the root URL of the server.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// BaseURL returns the URL to pass to sensibo.WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.srv.URL + "/api"
+}
+
+// APIKey returns the API key the server accepts.
+func (s *Server) APIKey() string {
+	return s.apiKey
+}
+
+// HTTPClient returns an http client configured for the server.
+func (s *Server) HTTPClient() *http.Client {
+	return s.srv.Client()
+}
+
+// NewClient returns a sensibo client that talks to the server,
+// opts are applied after the base URL, http client and API key options.
+func (s *Server) NewClient(opts ...sensibo.Option) *sensibo.Sensibo {
+	return sensibo.NewClient(s.apiKey, append([]sensibo.Option{
+		sensibo.WithBaseURL(s.BaseURL()),
+		sensibo.WithHTTPClient(s.HTTPClient()),
+	}, opts...)...)
+}
+
+// AddPod adds a pod or replaces the pod with the same device ID.
+func (s *Server) AddPod(pod Pod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addPod(pod)
+}
+
+// Pod returns a copy of the current state of a pod.
+func (s *Server) Pod(id string) (Pod, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.simulate()
+
+	p, ok := s.pods[id]
+
+	if !ok {
+		return Pod{}, false
+	}
+
+	return copyPod(*p), true
+}
+
+// LoadFixtures adds the pods of a JSON encoded Fixture.
+func (s *Server) LoadFixtures(r io.Reader) error {
+	fixture := Fixture{}
+
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return fmt.Errorf("failed decoding fixture \n\t%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range fixture.Pods {
+		s.addPod(p)
+	}
+
+	return nil
+}
+
+// LoadFixtureFile adds the pods of a JSON encoded Fixture file.
+func (s *Server) LoadFixtureFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return fmt.Errorf("failed reading fixture \n\t%w", err)
+	}
+
+	return s.LoadFixtures(bytes.NewReader(data))
+}
+
+func (s *Server) addPod(pod Pod) {
+	if pod.Device.ID == "" {
+		s.nextID++
+		pod.Device.ID = fmt.Sprintf("pod%d", s.nextID)
+	}
+
+	if _, ok := s.pods[pod.Device.ID]; !ok {
+		s.order = append(s.order, pod.Device.ID)
+	}
+
+	pod = copyPod(pod)
+	s.pods[pod.Device.ID] = &pod
+	delete(s.simulations, pod.Device.ID)
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+
+	return fmt.Sprintf("%08x", s.nextID)
+}
+
+// secondsAgo returns the seconds passed since t, 0 if t is not set.
+func (s *Server) secondsAgo(t models.Timestamp) float64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return s.now().Sub(t.Time).Seconds()
+}
+
+// copyPod returns a deep copy of pod.
+func copyPod(pod Pod) Pod {
+	data, err := json.Marshal(pod)
+
+	if err != nil {
+		panic(fmt.Sprintf("sensibotest: failed copying pod: %v", err))
+	}
+
+	cp := Pod{}
+
+	if err := json.Unmarshal(data, &cp); err != nil {
+		panic(fmt.Sprintf("sensibotest: failed copying pod: %v", err))
+	}
+
+	return cp
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sensibo "github.com/odinn1984/go-sensibo"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFixtureServer(t *testing.T, opts ...Option) *Server {
+	srv := NewServer(opts...)
+	t.Cleanup(srv.Close)
+
+	assert.NoError(t, srv.LoadFixtureFile("testdata/pods.json"))
+
+	return srv
+}
+
+func TestServer_devices(t *testing.T) {
+	srv := newFixtureServer(t)
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	devices, err := client.GetAllDevices(ctx, []string{"*"})
+
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+	assert.Equal(t, "abc123", devices[0].ID)
+	assert.Equal(t, "Living Room", devices[0].Room.Name)
+	assert.Equal(t, 26.5, devices[0].Measurements.Temperature)
+
+	devices, err = client.GetAllDevices(ctx, []string{"id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Device{{ID: "abc123"}, {ID: "def456"}}, devices)
+
+	_, err = client.GetDevice(ctx, "missing", []string{"*"})
+
+	assert.True(t, errors.Is(err, sensibo.ErrNotFound))
+}
+
+func TestServer_acStates(t *testing.T) {
+	srv := newFixtureServer(t)
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	state, err := client.SetDeviceACState(ctx, "abc123", models.ACStateData{
+		On:                true,
+		Mode:              "cool",
+		FanLevel:          "auto",
+		TargetTemperature: 22,
+		TemperatureUnit:   "C",
+		Swing:             "stopped",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", state.Status)
+	assert.Equal(t, []string{"on", "targetTemperature"}, state.ChangedProperties)
+
+	state, err = client.SetDeviceACStateProperty(ctx, "abc123", "fanLevel", "high")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fanLevel"}, state.ChangedProperties)
+
+	_, err = client.SetDeviceACStateProperty(ctx, "abc123", "targetTemperature", "warm")
+
+	assert.True(t, errors.Is(err, sensibo.ErrBadRequest))
+
+	states, err := client.GetDeviceACStates(ctx, "abc123", 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, states, 2)
+	assert.Equal(t, models.FanHigh, states[0].ACState.FanLevel)
+	assert.Equal(t, 22.0, states[1].ACState.TargetTemperature)
+
+	device, err := client.GetDevice(ctx, "abc123", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.True(t, device.ACState.On)
+	assert.Equal(t, models.FanHigh, device.ACState.FanLevel)
+
+	pod, ok := srv.Pod("abc123")
+
+	assert.True(t, ok)
+	assert.Len(t, pod.ACStates, 2)
+}
+
+func TestServer_updateACState(t *testing.T) {
+	srv := newFixtureServer(t)
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	patch := models.ACStatePatch{}.WithOn(true).WithLight(models.LightDim)
+	state, err := client.UpdateDeviceACState(ctx, "abc123", patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"on", "light"}, state.ChangedProperties)
+
+	device, err := client.GetDevice(ctx, "abc123", []string{"acState"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ACStateData{
+		On:                true,
+		Mode:              models.ModeCool,
+		FanLevel:          models.FanAuto,
+		TargetTemperature: 24,
+		TemperatureUnit:   models.UnitC,
+		Swing:             models.SwingStopped,
+		Light:             models.LightDim,
+	}, device.ACState.ACStateData)
+}
+
+func TestServer_fractionalTemperature(t *testing.T) {
+	srv := newFixtureServer(t)
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	state, err := client.SetDeviceACStateProperty(ctx, "abc123", "targetTemperature", "22.5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 22.5, state.ACState.TargetTemperature)
+
+	timer, err := client.SetDeviceTimer(ctx, "abc123", 30, models.ACStateData{On: true, TargetTemperature: 23.5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 23.5, timer.ACState.TargetTemperature)
+
+	device, err := client.GetDevice(ctx, "abc123", []string{"acState"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 22.5, device.ACState.TargetTemperature)
+}
+
+func TestServer_schedules(t *testing.T) {
+	srv := newFixtureServer(t)
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	created, err := client.CreateDeviceSchedule(ctx, "abc123", sensibo.CreateDeviceSchedulePayload{
+		TargetTimeLocal: "22:00",
+		TimeZone:        "Europe/London",
+		ACState:         models.ACStateData{On: false},
+		RecurringDays:   []string{"Monday"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, created.IsEnabled)
+	assert.Equal(t, "22:00", created.TargetTimeLocal)
+
+	toggled, err := client.ToggleDeviceSchedule(ctx, "abc123", created.ID, false)
+
+	assert.NoError(t, err)
+	assert.False(t, toggled.IsEnabled)
+
+	got, err := client.GetDeviceSchedule(ctx, "abc123", created.ID)
+
+	assert.NoError(t, err)
+	assert.False(t, got.IsEnabled)
+
+	assert.NoError(t, client.DeleteDeviceSchedule(ctx, "abc123", created.ID))
+
+	schedules, err := client.GetDeviceSchedules(ctx, "abc123")
+
+	assert.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	err = client.DeleteDeviceSchedule(ctx, "abc123", created.ID)
+
+	assert.True(t, errors.Is(err, sensibo.ErrNotFound))
+}
+
+func TestServer_timerAndClimateReact(t *testing.T) {
+	srv := newFixtureServer(t)
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	timer, err := client.SetDeviceTimer(ctx, "abc123", 30, models.ACStateData{On: true})
+
+	assert.NoError(t, err)
+	assert.True(t, timer.IsEnabled)
+	assert.InDelta(t, 30*60, timer.TargetTimeSecondsFromNow, 2)
+
+	got, err := client.GetDeviceTimer(ctx, "abc123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, timer.ID, got.ID)
+
+	assert.NoError(t, client.DeleteDeviceTimer(ctx, "abc123"))
+
+	got, err = client.GetDeviceTimer(ctx, "abc123")
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	climateReact, err := client.ToggleDeviceClimateReact(ctx, "abc123", true)
+
+	assert.NoError(t, err)
+	assert.True(t, climateReact.Enabled)
+
+	climateReact, err = client.GetDeviceClimateReactSettings(ctx, "abc123")
+
+	assert.NoError(t, err)
+	assert.True(t, climateReact.Enabled)
+}
+
+func TestServer_historicalMeasurements(t *testing.T) {
+	srv := newFixtureServer(t)
+	srv.now = func() time.Time { return time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC) }
+	client := srv.NewClient()
+
+	measurements, err := client.GetDeviceHistoricalMeasurements(context.Background(), "abc123", 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, measurements.Temperature, 1)
+	assert.Equal(t, 26.5, measurements.Temperature[0].Value)
+
+	srv.now = func() time.Time { return time.Date(2021, 6, 5, 0, 0, 0, 0, time.UTC) }
+
+	measurements, err = client.GetDeviceHistoricalMeasurements(context.Background(), "abc123", 1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, measurements.Temperature)
+}
+
+func TestServer_authentication(t *testing.T) {
+	srv := newFixtureServer(t, WithAPIKey("secret"))
+	ctx := context.Background()
+
+	_, err := srv.NewClient().GetAllDevices(ctx, []string{"id"})
+
+	assert.NoError(t, err)
+
+	_, err = srv.NewClient(sensibo.WithAuthorizationHeader()).GetAllDevices(ctx, []string{"id"})
+
+	assert.NoError(t, err)
+
+	client := sensibo.NewClient("wrong", sensibo.WithBaseURL(srv.BaseURL()))
+	_, err = client.GetAllDevices(ctx, []string{"id"})
+
+	assert.True(t, errors.Is(err, sensibo.ErrUnauthorized))
+}
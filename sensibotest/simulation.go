@@ -0,0 +1,271 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+const (
+	// simulationStep is the resolution of the room simulation.
+	simulationStep = time.Minute
+	// historyRetention is how long historical measurements are kept.
+	historyRetention = 7 * 24 * time.Hour
+)
+
+// Clock is a manually advanced clock, use it with WithClock to
+// fast-forward simulated time.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a clock set to start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// WithClock makes the server use clock instead of the system time.
+func WithClock(clock *Clock) Option {
+	return func(s *Server) {
+		s.now = clock.Now
+	}
+}
+
+// RoomModel is a simple thermal model of the room of a simulated pod,
+// zero values use the defaults.
+//
+// Temperatures are in Celsius, rates are per hour.
+type RoomModel struct {
+	// OutdoorTemperature is what the room drifts to with the AC off, 30 by default.
+	OutdoorTemperature float64 `json:"outdoorTemperature"`
+	// OutdoorHumidity is what the humidity drifts to with the AC off, 60 by default.
+	OutdoorHumidity float64 `json:"outdoorHumidity"`
+	// Leakage is the fraction of the indoor/outdoor difference
+	// that leaks every hour (0 to 1), 0.2 by default.
+	Leakage float64 `json:"leakage"`
+	// Power is how many degrees the AC moves the temperature every
+	// hour on the highest fan level, 6 by default.
+	Power float64 `json:"power"`
+	// Dehumidification is how many humidity percents cool and dry
+	// modes remove every hour on the highest fan level, 10 by default.
+	Dehumidification float64 `json:"dehumidification"`
+}
+
+// Simulation makes the measurements of a pod evolve over time according
+// to its AC state. The simulation starts from the pod's measurements.
+type Simulation struct {
+	Room RoomModel `json:"room"`
+	// SampleInterval is the interval of the historical measurements,
+	// 5 minutes by default. It is in nanoseconds in fixtures.
+	SampleInterval time.Duration `json:"sampleInterval"`
+}
+
+// simulationState is the progress of the simulation of a pod.
+type simulationState struct {
+	last       time.Time
+	lastSample time.Time
+}
+
+func (m RoomModel) withDefaults() RoomModel {
+	if m.OutdoorTemperature == 0 {
+		m.OutdoorTemperature = 30
+	}
+
+	if m.OutdoorHumidity == 0 {
+		m.OutdoorHumidity = 60
+	}
+
+	if m.Leakage <= 0 {
+		m.Leakage = 0.2
+	}
+
+	if m.Power <= 0 {
+		m.Power = 6
+	}
+
+	if m.Dehumidification <= 0 {
+		m.Dehumidification = 10
+	}
+
+	return m
+}
+
+// simulate advances timers and simulated pods to the current time.
+// It must be called with mu held.
+func (s *Server) simulate() {
+	now := s.now()
+
+	for _, id := range s.order {
+		p := s.pods[id]
+		state, ok := s.simulations[id]
+
+		if !ok {
+			state = &simulationState{last: now, lastSample: now}
+			s.simulations[id] = state
+
+			if p.Simulation != nil {
+				s.sample(p, now)
+			}
+		}
+
+		for state.last.Before(now) {
+			step := simulationStep
+
+			if remaining := now.Sub(state.last); remaining < step {
+				step = remaining
+			}
+
+			state.last = state.last.Add(step)
+			s.fireTimer(p, state.last)
+
+			if p.Simulation == nil {
+				continue
+			}
+
+			p.Simulation.Room.step(p, step)
+
+			interval := p.Simulation.SampleInterval
+
+			if interval <= 0 {
+				interval = 5 * time.Minute
+			}
+
+			if state.last.Sub(state.lastSample) >= interval {
+				state.lastSample = state.last
+				s.sample(p, state.last)
+			}
+		}
+
+		if p.Simulation != nil {
+			p.Device.Measurements.Time.Time = models.NewTimestamp(now)
+		}
+	}
+}
+
+// fireTimer applies the AC state of the pod's timer once its target time passed.
+func (s *Server) fireTimer(p *Pod, now time.Time) {
+	if p.Timer == nil || !p.Timer.IsEnabled {
+		return
+	}
+
+	target := p.Timer.TargetTime
+
+	if target.IsZero() || target.After(now) {
+		return
+	}
+
+	state := p.Timer.ACState
+	p.Timer = nil
+
+	s.setACState(p, state, "Timer", now)
+}
+
+// sample appends the current measurements to the history of the pod.
+func (s *Server) sample(p *Pod, at time.Time) {
+	t := models.NewTimestamp(at)
+	h := &p.HistoricalMeasurements
+
+	h.Temperature = append(h.Temperature, models.Measurement{Time: t, Value: round(p.Device.Measurements.Temperature)})
+	h.Humidity = append(h.Humidity, models.Measurement{Time: t, Value: round(p.Device.Measurements.Humidity)})
+
+	oldest := at.Add(-historyRetention)
+
+	for len(h.Temperature) > 0 && !s.after(h.Temperature[0].Time, oldest) {
+		h.Temperature = h.Temperature[1:]
+	}
+
+	for len(h.Humidity) > 0 && !s.after(h.Humidity[0].Time, oldest) {
+		h.Humidity = h.Humidity[1:]
+	}
+}
+
+// step advances the measurements of p by d.
+func (m RoomModel) step(p *Pod, d time.Duration) {
+	m = m.withDefaults()
+	hours := d.Hours()
+	measurements := &p.Device.Measurements
+	ac := p.Device.ACState.ACStateData
+
+	temperature := measurements.Temperature
+	humidity := measurements.Humidity
+
+	temperature += m.Leakage * (m.OutdoorTemperature - temperature) * hours
+	humidity += m.Leakage * (m.OutdoorHumidity - humidity) * hours
+
+	if ac.On {
+		fan := fanFactor(ac.FanLevel)
+		target := ac.TargetTemperature
+
+		if ac.TemperatureUnit == models.UnitF {
+			target = (target - 32) * 5 / 9
+		}
+
+		// the AC slows down when getting close to the target temperature
+		drive := math.Max(-1, math.Min(1, target-temperature)) * m.Power * fan * hours
+
+		switch ac.Mode {
+		case models.ModeCool:
+			temperature += math.Min(0, drive)
+			humidity -= m.Dehumidification * fan * hours / 2
+		case models.ModeHeat:
+			temperature += math.Max(0, drive)
+		case models.ModeAuto:
+			temperature += drive
+		case models.ModeDry:
+			temperature += math.Min(0, drive) / 4
+			humidity -= m.Dehumidification * fan * hours
+		case models.ModeFan:
+		}
+	}
+
+	measurements.Temperature = temperature
+	measurements.Humidity = math.Max(0, math.Min(100, humidity))
+}
+
+func fanFactor(level models.FanLevel) float64 {
+	switch level {
+	case models.FanQuiet:
+		return 0.4
+	case models.FanLow:
+		return 0.6
+	case models.FanMediumLow:
+		return 0.7
+	case models.FanMedium:
+		return 0.8
+	case models.FanMediumHigh:
+		return 0.9
+	case models.FanHigh, models.FanAuto:
+		return 1
+	case models.FanStrong:
+		return 1.2
+	}
+
+	return 1
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
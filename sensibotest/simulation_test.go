@@ -0,0 +1,166 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSimulatedServer(t *testing.T, state models.ACStateData) (*Server, *Clock) {
+	clock := NewClock(time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC))
+	pod := Pod{
+		Device:     models.Device{ID: "sim"},
+		Simulation: &Simulation{Room: RoomModel{OutdoorTemperature: 32, OutdoorHumidity: 70}},
+	}
+	pod.Device.ACState.ACStateData = state
+	pod.Device.Measurements.Temperature = 28
+	pod.Device.Measurements.Humidity = 55
+
+	srv := NewServer(WithClock(clock), WithPods(pod))
+	t.Cleanup(srv.Close)
+
+	return srv, clock
+}
+
+func TestSimulation(t *testing.T) {
+	tests := []struct {
+		name  string
+		state models.ACStateData
+		check func(t *testing.T, temperature float64, humidity float64)
+	}{
+		{
+			name:  "off drifts to outdoor",
+			state: models.ACStateData{On: false},
+			check: func(t *testing.T, temperature float64, humidity float64) {
+				assert.InDelta(t, 31, temperature, 1)
+				assert.InDelta(t, 67, humidity, 2)
+			},
+		},
+		{
+			name:  "cool reaches target",
+			state: models.ACStateData{On: true, Mode: "cool", FanLevel: "high", TargetTemperature: 22, TemperatureUnit: "C"},
+			check: func(t *testing.T, temperature float64, humidity float64) {
+				assert.InDelta(t, 22, temperature, 2)
+				assert.Less(t, humidity, 55.0)
+			},
+		},
+		{
+			name:  "heat in fahrenheit",
+			state: models.ACStateData{On: true, Mode: "heat", FanLevel: "auto", TargetTemperature: 95, TemperatureUnit: "F"},
+			check: func(t *testing.T, temperature float64, humidity float64) {
+				assert.InDelta(t, 35, temperature, 1)
+			},
+		},
+		{
+			name:  "heat above target does nothing",
+			state: models.ACStateData{On: true, Mode: "heat", FanLevel: "auto", TargetTemperature: 18, TemperatureUnit: "C"},
+			check: func(t *testing.T, temperature float64, humidity float64) {
+				assert.InDelta(t, 31, temperature, 1)
+			},
+		},
+		{
+			name:  "dry removes humidity",
+			state: models.ACStateData{On: true, Mode: "dry", FanLevel: "high", TargetTemperature: 24, TemperatureUnit: "C"},
+			check: func(t *testing.T, temperature float64, humidity float64) {
+				assert.Less(t, humidity, 30.0)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, clock := newSimulatedServer(t, tt.state)
+			client := srv.NewClient()
+			ctx := context.Background()
+
+			_, err := client.GetDevice(ctx, "sim", []string{"*"})
+			assert.NoError(t, err)
+
+			clock.Advance(12 * time.Hour)
+
+			device, err := client.GetDevice(ctx, "sim", []string{"*"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "2021-07-02T00:00:00Z", device.Measurements.Time.Time.String())
+			tt.check(t, device.Measurements.Temperature, device.Measurements.Humidity)
+		})
+	}
+}
+
+func TestSimulation_historicalMeasurements(t *testing.T) {
+	srv, clock := newSimulatedServer(t, models.ACStateData{
+		On:                true,
+		Mode:              "cool",
+		FanLevel:          "high",
+		TargetTemperature: 20,
+		TemperatureUnit:   "C",
+	})
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	_, err := client.GetDevice(ctx, "sim", []string{"id"})
+	assert.NoError(t, err)
+
+	clock.Advance(time.Hour)
+
+	history, err := client.GetDeviceHistoricalMeasurements(ctx, "sim", 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, history.Temperature, 13)
+	assert.Len(t, history.Humidity, 13)
+	assert.Equal(t, "2021-07-01T12:00:00Z", history.Temperature[0].Time.String())
+	assert.Equal(t, 28.0, history.Temperature[0].Value)
+	assert.Equal(t, "2021-07-01T13:00:00Z", history.Temperature[12].Time.String())
+	assert.Less(t, history.Temperature[12].Value, history.Temperature[0].Value)
+
+	clock.Advance(10 * 24 * time.Hour)
+
+	history, err = client.GetDeviceHistoricalMeasurements(ctx, "sim", 30)
+
+	assert.NoError(t, err)
+	assert.Len(t, history.Temperature, 7*24*12)
+}
+
+func TestSimulation_timerFires(t *testing.T) {
+	srv, clock := newSimulatedServer(t, models.ACStateData{On: false})
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	_, err := client.SetDeviceTimer(ctx, "sim", 30, models.ACStateData{
+		On:                true,
+		Mode:              "cool",
+		FanLevel:          "high",
+		TargetTemperature: 20,
+		TemperatureUnit:   "C",
+	})
+	assert.NoError(t, err)
+
+	clock.Advance(29 * time.Minute)
+
+	device, err := client.GetDevice(ctx, "sim", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.False(t, device.ACState.On)
+
+	clock.Advance(2 * time.Hour)
+
+	device, err = client.GetDevice(ctx, "sim", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.True(t, device.ACState.On)
+	assert.Less(t, device.Measurements.Temperature, 27.0)
+
+	states, err := client.GetDeviceACStates(ctx, "sim", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Timer", states[0].Reason)
+	assert.Equal(t, "2021-07-01T12:30:00Z", states[0].ACState.Timestamp.Time.String())
+	assert.Equal(t, float64(119*60), states[0].ACState.Timestamp.SecondsAgo)
+}
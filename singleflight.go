@@ -0,0 +1,161 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// WithRequestCoalescing makes concurrent identical GET requests share a
+// single round trip to the API and a single decoded result.
+//
+// A caller whose context is done stops waiting without affecting the
+// others, the shared request is cancelled once no caller waits for it.
+//
+// Results are shared with shallow copies, callers must not modify slices
+// or maps of returned values if they need them to stay independent.
+func WithRequestCoalescing() Option {
+	return func(s *Sensibo) {
+		s.flights = &flightGroup{}
+	}
+}
+
+// makeCoalescedRequest makes GET requests through the flight group.
+func (s *Sensibo) makeCoalescedRequest(ctx context.Context, r request, payload []byte) error {
+	if s.flights == nil || r.method != http.MethodGet {
+		return s.makeAttempts(ctx, r, payload)
+	}
+
+	key := fmt.Sprintf("%T %s", r.result, requestKey(r))
+
+	shared, err := s.flights.do(ctx, key, s.timeout, func(ctx context.Context) (interface{}, error) {
+		var result interface{}
+
+		if r.result != nil {
+			result = reflect.New(reflect.TypeOf(r.result).Elem()).Interface()
+		}
+
+		flight := r
+		flight.result = result
+
+		return result, s.makeAttempts(ctx, flight, payload)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if r.result != nil {
+		reflect.ValueOf(r.result).Elem().Set(reflect.ValueOf(shared).Elem())
+	}
+
+	return nil
+}
+
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	result  interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do runs fn once for all concurrent callers with the same key.
+//
+// fn runs with a context that keeps the values of the first caller's
+// context, it is cancelled when all callers stopped waiting or after timeout.
+func (g *flightGroup) do(
+	ctx context.Context,
+	key string,
+	timeout time.Duration,
+	fn func(ctx context.Context) (interface{}, error),
+) (interface{}, error) {
+	g.mu.Lock()
+
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+
+	call, ok := g.calls[key]
+
+	if !ok {
+		var flightCtx context.Context
+
+		call = &flightCall{done: make(chan struct{})}
+
+		if timeout > 0 {
+			flightCtx, call.cancel = context.WithTimeout(detachedContext{ctx}, timeout)
+		} else {
+			flightCtx, call.cancel = context.WithCancel(detachedContext{ctx})
+		}
+
+		g.calls[key] = call
+
+		go func() {
+			call.result, call.err = fn(flightCtx)
+
+			g.mu.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+
+			call.cancel()
+			close(call.done)
+		}()
+	}
+
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+
+		// Nobody waits anymore, later callers start a new flight.
+		if call.waiters == 0 {
+			delete(g.calls, key)
+			call.cancel()
+		}
+		g.mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext keeps the values of its parent but not its
+// cancellation or deadline.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newBlockingClient returns a client whose requests block until release is closed.
+func newBlockingClient(release chan struct{}, opts ...Option) (*Sensibo, *int32, chan struct{}) {
+	var calls int32
+
+	started := make(chan struct{}, 10)
+
+	return newMockClient(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		started <- struct{}{}
+
+		select {
+		case <-release:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		return &http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader(`{"status":"success","result":{"id":"1234"}}`)),
+			StatusCode: 200,
+		}, nil
+	}, opts...), &calls, started
+}
+
+func TestWithRequestCoalescing_sharesRoundTrip(t *testing.T) {
+	release := make(chan struct{})
+	s, calls, started := newBlockingClient(release, WithRequestCoalescing())
+
+	var wg sync.WaitGroup
+
+	results := make([]*models.Device, 5)
+	errs := make([]error, 5)
+
+	for i := range results {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i], errs[i] = s.GetDevice(context.Background(), "1234", []string{"*"})
+		}(i)
+	}
+
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	for i := range results {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, &models.Device{ID: "1234"}, results[i])
+	}
+}
+
+func TestWithRequestCoalescing_differentRequests(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+
+	s, calls, _ := newBlockingClient(release, WithRequestCoalescing())
+
+	_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+	assert.NoError(t, err)
+
+	_, err = s.GetDevice(context.Background(), "1234", []string{"id"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestWithRequestCoalescing_callerCancelled(t *testing.T) {
+	release := make(chan struct{})
+	s, calls, started := newBlockingClient(release, WithRequestCoalescing())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error, 1)
+
+	go func() {
+		_, err := s.GetDevice(ctx, "1234", []string{"*"})
+		cancelledErr <- err
+	}()
+
+	<-started
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-cancelledErr, context.Canceled)
+
+	// the other caller still gets the shared result
+	close(release)
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestWithRequestCoalescing_allCallersCancelled(t *testing.T) {
+	release := make(chan struct{})
+	s, calls, started := newBlockingClient(release, WithRequestCoalescing())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error, 1)
+
+	go func() {
+		_, err := s.GetDevice(ctx, "1234", []string{"*"})
+		cancelledErr <- err
+	}()
+
+	<-started
+	cancel()
+
+	assert.ErrorIs(t, <-cancelledErr, context.Canceled)
+
+	// the abandoned flight is not joined by later callers
+	close(release)
+
+	got, err := s.GetDevice(context.Background(), "1234", []string{"*"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Device{ID: "1234"}, got)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
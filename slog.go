@@ -0,0 +1,40 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package sensibo
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogObserver returns an Observer that logs every attempt to logger.
+//
+// Successful attempts are logged at debug level, failed ones at warn level.
+func SlogObserver(logger *slog.Logger) Observer {
+	return func(ctx context.Context, event RequestEvent) {
+		attrs := []slog.Attr{
+			slog.String("operation", event.Operation),
+			slog.String("device_id", event.DeviceID),
+			slog.String("method", event.Method),
+			slog.String("api_version", event.Version),
+			slog.String("endpoint", event.Endpoint),
+			slog.Int("attempt", event.Attempt),
+			slog.Int("status_code", event.StatusCode),
+			slog.Duration("latency", event.Latency),
+		}
+
+		if event.Err != nil {
+			attrs = append(attrs, slog.String("error", event.Err.Error()))
+			logger.LogAttrs(ctx, slog.LevelWarn, "sensibo request failed", attrs...)
+
+			return
+		}
+
+		logger.LogAttrs(ctx, slog.LevelDebug, "sensibo request", attrs...)
+	}
+}
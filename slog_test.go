@@ -0,0 +1,54 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package sensibo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogObserver(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	s := NewClient(
+		"secret-key",
+		WithHTTPClient(&mocks.HTTPClientMock{
+			DoMock: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					Body:       ioutil.NopCloser(strings.NewReader("nope secret-key")),
+					StatusCode: http.StatusBadRequest,
+				}, nil
+			},
+		}),
+		WithObserver(SlogObserver(logger)),
+	)
+
+	err := s.DeleteDeviceTimer(context.Background(), "1234")
+
+	assert.Error(t, err)
+	assert.NotContains(t, buf.String(), "secret-key")
+
+	entry := map[string]interface{}{}
+
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "DeleteDeviceTimer", entry["operation"])
+	assert.Equal(t, "1234", entry["device_id"])
+	assert.Equal(t, "v1", entry["api_version"])
+	assert.Equal(t, float64(400), entry["status_code"])
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+// deviceStateFields are the fields of the device that rounding and validation
+// of AC states need, they all request the same fields to share cached responses.
+var deviceStateFields = []string{"acState", "remoteCapabilities", "temperatureUnit"}
+
+// getDeviceState gets the fields of the device needed to round and validate AC states.
+func (s *Sensibo) getDeviceState(ctx context.Context, id string) (*models.Device, error) {
+	return s.GetDevice(ctx, id, deviceStateFields)
+}
+
+// prepareTemperature returns state with float noise dropped from its target
+// temperature, see models.NormalizeTemperature. When validation is enabled
+// fractional temperatures are also rounded to whole degrees for devices
+// that only support whole ones, which gets the device first.
+func (s *Sensibo) prepareTemperature(ctx context.Context, id string, state models.ACStateData) (models.ACStateData, error) {
+	state.TargetTemperature = models.NormalizeTemperature(state.TargetTemperature)
+
+	if s.validation == nil || state.TargetTemperature == math.Trunc(state.TargetTemperature) {
+		return state, nil
+	}
+
+	device, err := s.getDeviceState(ctx, id)
+
+	if err != nil {
+		return state, fmt.Errorf("failed getting supported temperatures \n\t%w", err)
+	}
+
+	return roundTemperature(device, state), nil
+}
+
+// roundTemperature returns state with its target temperature rounded to
+// whole degrees when the device only supports whole ones. The device's
+// mode and unit are used when state has none.
+func roundTemperature(device *models.Device, state models.ACStateData) models.ACStateData {
+	check := withDeviceUnit(state, device)
+
+	if check.Mode == "" {
+		check.Mode = device.ACState.Mode
+	}
+
+	state.TargetTemperature = device.RemoteCapabilities.RoundTemperature(check).TargetTemperature
+
+	return state
+}
+
+// parseTemperature parses the value of the targetTemperature property,
+// it reports false for values that are not numbers.
+func parseTemperature(value string) (float64, bool) {
+	temperature, err := strconv.ParseFloat(value, 64)
+
+	if err != nil || math.IsNaN(temperature) || math.IsInf(temperature, 0) {
+		return 0, false
+	}
+
+	return temperature, true
+}
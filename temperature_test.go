@@ -0,0 +1,157 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// cool supports only whole temperatures, heat supports half degrees
+const temperaturesResult = `{
+	"temperatureUnit": "C",
+	"acState": {"mode": "heat"},
+	"remoteCapabilities": {"modes": {
+		"cool": {"temperatures": {"C": {"values": [20, 21, 22]}}},
+		"heat": {"temperatures": {"C": {"values": [20, 20.5, 21]}}}
+	}}
+}`
+
+func TestSensibo_fractionalTemperatures_withoutValidation(t *testing.T) {
+	router := mocks.NewRouter()
+	router.Expect(http.MethodPost, "v2/pods/{id}/acStates").
+		WithJSONBody(`{"acState":{"targetTemperature":22}}`).
+		ReturnResult(map[string]string{"id": "1"})
+	router.Expect(http.MethodPut, "v1/pods/{id}/timer").
+		WithJSONBody(`{"acState":{"targetTemperature":20.6}}`).
+		ReturnResult(map[string]string{"id": "1"})
+
+	s := NewClient("api-key", WithHTTPClient(router.Client()))
+
+	// float noise is dropped and the device is never fetched
+	_, err := s.SetDeviceACState(context.Background(), "1234", models.ACStateData{TargetTemperature: 22.000000000000004})
+	assert.NoError(t, err)
+
+	_, err = s.SetDeviceTimer(context.Background(), "1234", 30, models.ACStateData{TargetTemperature: 20.60000000000001})
+	assert.NoError(t, err)
+
+	router.Verify(t)
+}
+
+func TestSensibo_fractionalTemperatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(s *Sensibo) error
+		method   string
+		endpoint string
+		sent     string
+	}{
+		{
+			name: "SetDeviceACState rounds for whole degree modes",
+			call: func(s *Sensibo) error {
+				_, err := s.SetDeviceACState(context.Background(), "1234", models.ACStateData{Mode: models.ModeCool, TargetTemperature: 21.5})
+
+				return err
+			},
+			method:   http.MethodPost,
+			endpoint: "v2/pods/{id}/acStates",
+			sent:     `{"acState":{"targetTemperature":22}}`,
+		},
+		{
+			name: "SetDeviceACState keeps half degrees in the device's mode",
+			call: func(s *Sensibo) error {
+				_, err := s.SetDeviceACState(context.Background(), "1234", models.ACStateData{TargetTemperature: 20.5})
+
+				return err
+			},
+			method:   http.MethodPost,
+			endpoint: "v2/pods/{id}/acStates",
+			sent:     `{"acState":{"targetTemperature":20.5}}`,
+		},
+		{
+			name: "UpdateDeviceACState",
+			call: func(s *Sensibo) error {
+				patch := models.ACStatePatch{}.WithMode(models.ModeCool).WithTargetTemperature(21.7)
+				_, err := s.UpdateDeviceACState(context.Background(), "1234", patch)
+
+				return err
+			},
+			method:   http.MethodPost,
+			endpoint: "v2/pods/{id}/acStates",
+			sent:     `{"acState":{"mode":"cool","targetTemperature":22}}`,
+		},
+		{
+			name: "SetDeviceACStateProperty",
+			call: func(s *Sensibo) error {
+				_, err := s.SetDeviceACStateProperty(context.Background(), "1234", "targetTemperature", "20.50000000001")
+
+				return err
+			},
+			method:   http.MethodPatch,
+			endpoint: "v2/pods/{id}/acStates/targetTemperature",
+			sent:     `{"newValue":"20.5"}`,
+		},
+		{
+			name: "SetDeviceTimer",
+			call: func(s *Sensibo) error {
+				_, err := s.SetDeviceTimer(context.Background(), "1234", 30, models.ACStateData{Mode: models.ModeCool, TargetTemperature: 20.6})
+
+				return err
+			},
+			method:   http.MethodPut,
+			endpoint: "v1/pods/{id}/timer",
+			sent:     `{"minutesFromNow":30,"acState":{"targetTemperature":21}}`,
+		},
+		{
+			name: "CreateDeviceSchedule",
+			call: func(s *Sensibo) error {
+				_, err := s.CreateDeviceSchedule(context.Background(), "1234", CreateDeviceSchedulePayload{
+					ACState: models.ACStateData{Mode: models.ModeCool, TargetTemperature: 20.4},
+				})
+
+				return err
+			},
+			method:   http.MethodPost,
+			endpoint: "v1/pods/{id}/schedules",
+			sent:     `{"acState":{"targetTemperature":20}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mocks.NewRouter()
+
+			// rounding and validation share a single request
+			router.Expect(http.MethodGet, "v2/pods/{id}").
+				WithQuery("fields", "acState,remoteCapabilities,temperatureUnit").
+				Return(http.StatusOK, `{"status":"success","result":`+temperaturesResult+`}`)
+			router.Expect(tt.method, tt.endpoint).
+				WithJSONBody(tt.sent).
+				ReturnResult(map[string]string{"id": "1"})
+
+			s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(ValidationConfig{}))
+
+			assert.NoError(t, tt.call(s))
+			router.Verify(t)
+		})
+	}
+}
+
+func TestSensibo_fractionalTemperatures_deviceError(t *testing.T) {
+	router := mocks.NewRouter()
+	router.Expect(http.MethodGet, "v2/pods/{id}").ReturnError(http.StatusNotFound, "NotFound", "")
+
+	s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(ValidationConfig{}))
+	_, err := s.SetDeviceTimer(context.Background(), "1234", 30, models.ACStateData{TargetTemperature: 22.5})
+
+	assert.EqualError(t, err, "failed setting timer: \n\tfailed getting supported temperatures \n\t"+
+		"failed getting device \n\tsensibo: GET v2/pods/1234: 404 Not Found: NotFound")
+	router.Verify(t)
+}
@@ -0,0 +1,182 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/odinn1984/go-sensibo/models"
+)
+
+// ErrUnsupportedACState is matched by errors returned when validation
+// rejects an AC state, see WithValidation.
+var ErrUnsupportedACState = errors.New("sensibo: AC state not supported by the device")
+
+// ValidationError holds the values of an AC state that the device does not
+// support.
+type ValidationError struct {
+	DeviceID   string
+	Violations []models.Violation
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+
+	for _, v := range e.Violations {
+		violations = append(violations, v.String())
+	}
+
+	return fmt.Sprintf("sensibo: AC state not supported by device %s: %s", e.DeviceID, strings.Join(violations, "; "))
+}
+
+// Is reports whether target is ErrUnsupportedACState.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrUnsupportedACState
+}
+
+// ValidationConfig configures the validation enabled by WithValidation.
+type ValidationConfig struct {
+	// SnapTemperature replaces unsupported target temperatures with the
+	// closest supported one instead of failing.
+	SnapTemperature bool
+	// OnUnknownValues is called with the unknown values of a state (e.g: a
+	// typo like "coool") when the device's capabilities are unknown. Such
+	// states can't be checked and are sent anyway.
+	OnUnknownValues func(deviceID string, violations []models.Violation)
+}
+
+// WithValidation makes SetDeviceACState, UpdateDeviceACState and
+// SetDeviceACStateProperty check the new state against the device's
+// remote capabilities before sending it, unsupported states fail with
+// a *ValidationError.
+//
+// Fractional target temperatures, including those of SetDeviceTimer
+// and CreateDeviceSchedule, are rounded to whole degrees for devices
+// that only support whole ones.
+//
+// Every validation gets the device once first, use WithCache to avoid
+// an extra request per call.
+func WithValidation(config ValidationConfig) Option {
+	return func(s *Sensibo) {
+		s.validation = &config
+	}
+}
+
+// validateACState returns state, with a snapped temperature if enabled,
+// or an error if the device does not support it.
+func (s *Sensibo) validateACState(ctx context.Context, id string, state models.ACStateData) (models.ACStateData, error) {
+	device, err := s.getDeviceState(ctx, id)
+
+	if err != nil {
+		return state, fmt.Errorf("failed validating ac state \n\t%w", err)
+	}
+
+	capabilities := device.RemoteCapabilities
+	state = roundTemperature(device, state)
+	check := withDeviceUnit(state, device)
+
+	if s.validation.SnapTemperature {
+		check = capabilities.SnapTemperature(check)
+		state.TargetTemperature = check.TargetTemperature
+	}
+
+	if err := s.checkViolations(id, capabilities, capabilities.Validate(check)); err != nil {
+		return state, err
+	}
+
+	return state, nil
+}
+
+// validateACStatePatch returns patch, with a snapped temperature if enabled,
+// or an error if the device does not support the state that results from applying it.
+func (s *Sensibo) validateACStatePatch(ctx context.Context, id string, patch models.ACStatePatch) (models.ACStatePatch, error) {
+	device, err := s.getDeviceState(ctx, id)
+
+	if err != nil {
+		return patch, fmt.Errorf("failed validating ac state \n\t%w", err)
+	}
+
+	capabilities := device.RemoteCapabilities
+	state := withDeviceUnit(patch.Apply(device.ACState.ACStateData), device)
+
+	if patch.TargetTemperature != nil {
+		state = roundTemperature(device, state)
+		patch = patch.WithTargetTemperature(state.TargetTemperature)
+	}
+
+	if s.validation.SnapTemperature && patch.TargetTemperature != nil {
+		state = capabilities.SnapTemperature(state)
+		patch = patch.WithTargetTemperature(state.TargetTemperature)
+	}
+
+	if err := s.checkViolations(id, capabilities, capabilities.Validate(state)); err != nil {
+		return patch, err
+	}
+
+	return patch, nil
+}
+
+// validateACStateProperty returns value, snapped if enabled, or an error if
+// the device does not support the state that results from setting property.
+func (s *Sensibo) validateACStateProperty(ctx context.Context, id string, property string, value string) (string, error) {
+	device, err := s.getDeviceState(ctx, id)
+
+	if err != nil {
+		return value, fmt.Errorf("failed validating ac state \n\t%w", err)
+	}
+
+	capabilities := device.RemoteCapabilities
+	current := withDeviceUnit(device.ACState.ACStateData, device)
+
+	if temperature, ok := parseTemperature(value); ok && property == "targetTemperature" {
+		state := current
+		state.TargetTemperature = temperature
+		state = roundTemperature(device, state)
+
+		if s.validation.SnapTemperature {
+			state = capabilities.SnapTemperature(state)
+		}
+
+		value = models.FormatTemperature(state.TargetTemperature)
+	}
+
+	if err := s.checkViolations(id, capabilities, capabilities.ValidateProperty(current, property, value)); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// checkViolations returns a *ValidationError for violations, unless the
+// capabilities are unknown and the violations are only unknown values.
+func (s *Sensibo) checkViolations(id string, capabilities models.RemoteCapabilities, violations []models.Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if len(capabilities.Modes) == 0 {
+		if s.validation.OnUnknownValues != nil {
+			s.validation.OnUnknownValues(id, violations)
+		}
+
+		return nil
+	}
+
+	return &ValidationError{DeviceID: id, Violations: violations}
+}
+
+// withDeviceUnit returns state with the temperature unit of the device
+// when it has none, the API uses the device's unit in that case.
+func withDeviceUnit(state models.ACStateData, device *models.Device) models.ACStateData {
+	if state.TemperatureUnit == "" {
+		state.TemperatureUnit = device.TemperatureUnit
+	}
+
+	return state
+}
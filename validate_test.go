@@ -0,0 +1,195 @@
+// Copyright 2021 To Levan Giguashvili. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package sensibo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/odinn1984/go-sensibo/mocks"
+	"github.com/odinn1984/go-sensibo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const capabilitiesResult = `{
+	"temperatureUnit": "C",
+	"acState": {"on": true, "mode": "cool", "fanLevel": "high", "targetTemperature": 22, "temperatureUnit": "C"},
+	"remoteCapabilities": {"modes": {
+		"cool": {"temperatures": {"C": {"values": [18, 20, 22]}}, "fanLevels": ["low", "high"], "swing": ["stopped"]},
+		"fan": {"fanLevels": ["low"]}
+	}}
+}`
+
+func expectCapabilities(router *mocks.Router, fields string) {
+	router.Expect(http.MethodGet, "v2/pods/{id}").
+		WithQuery("fields", fields).
+		Return(http.StatusOK, `{"status":"success","result":`+capabilitiesResult+`}`)
+}
+
+func TestWithValidation_SetDeviceACState(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ValidationConfig
+		state  models.ACStateData
+		sent   interface{}
+		err    string
+	}{
+		{
+			name:   "supported state is sent",
+			state:  models.ACStateData{On: true, Mode: models.ModeCool, FanLevel: models.FanLow, TargetTemperature: 20, TemperatureUnit: models.UnitC},
+			sent:   map[string]interface{}{"acState": map[string]interface{}{"fanLevel": "low", "targetTemperature": 20}},
+			config: ValidationConfig{},
+		},
+		{
+			name:   "temperature is snapped",
+			state:  models.ACStateData{On: true, Mode: models.ModeCool, TargetTemperature: 23, TemperatureUnit: models.UnitC},
+			sent:   map[string]interface{}{"acState": map[string]interface{}{"targetTemperature": 22}},
+			config: ValidationConfig{SnapTemperature: true},
+		},
+		{
+			name:  "temperature is checked in the unit of the device",
+			state: models.ACStateData{On: true, Mode: models.ModeCool, TargetTemperature: 99},
+			err: "failed setting device ac state: \n\tsensibo: AC state not supported by device 1234: " +
+				`targetTemperature "99" is not supported (supported: 18, 20, 22)`,
+		},
+		{
+			name:   "temperature is snapped in the unit of the device",
+			state:  models.ACStateData{On: true, Mode: models.ModeCool, TargetTemperature: 99},
+			sent:   map[string]interface{}{"acState": map[string]interface{}{"targetTemperature": 22, "temperatureUnit": ""}},
+			config: ValidationConfig{SnapTemperature: true},
+		},
+		{
+			name:  "unsupported state is not sent",
+			state: models.ACStateData{On: true, Mode: models.ModeCool, FanLevel: "turbo", TargetTemperature: 23, TemperatureUnit: models.UnitC},
+			err: "failed setting device ac state: \n\tsensibo: AC state not supported by device 1234: " +
+				`targetTemperature "23" is not supported (supported: 18, 20, 22); ` +
+				`fanLevel "turbo" is not supported (supported: low, high)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mocks.NewRouter()
+			expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+
+			if tt.sent != nil {
+				router.Expect(http.MethodPost, "v2/pods/{id}/acStates").
+					WithJSONBody(tt.sent).
+					ReturnResult(map[string]string{"status": "Success"})
+			}
+
+			s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(tt.config))
+			_, err := s.SetDeviceACState(context.Background(), "1234", tt.state)
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+				assert.True(t, errors.Is(err, ErrUnsupportedACState))
+			}
+
+			router.Verify(t)
+		})
+	}
+}
+
+func TestWithValidation_SetDeviceACStateProperty(t *testing.T) {
+	router := mocks.NewRouter()
+	expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+	expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+	expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+	expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+	router.Expect(http.MethodPatch, "v2/pods/{id}/acStates/targetTemperature").
+		WithJSONBody(`{"newValue":"18"}`).
+		ReturnResult(map[string]string{"status": "Success"})
+	router.Expect(http.MethodPatch, "v2/pods/{id}/acStates/fanLevel").
+		WithJSONBody(`{"newValue":"low"}`).
+		ReturnResult(map[string]string{"status": "Success"})
+	router.Expect(http.MethodPatch, "v2/pods/{id}/acStates/turbo").
+		WithJSONBody(`{"newValue":"on"}`).
+		ReturnResult(map[string]string{"status": "Success"})
+
+	s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(ValidationConfig{SnapTemperature: true}))
+
+	_, err := s.SetDeviceACStateProperty(context.Background(), "1234", "targetTemperature", "17")
+	assert.NoError(t, err)
+
+	_, err = s.SetDeviceACStateProperty(context.Background(), "1234", "fanLevel", "low")
+	assert.NoError(t, err)
+
+	// properties unknown to the client are sent as is
+	_, err = s.SetDeviceACStateProperty(context.Background(), "1234", "turbo", "on")
+	assert.NoError(t, err)
+
+	// high fan level is not supported in fan mode
+	_, err = s.SetDeviceACStateProperty(context.Background(), "1234", "mode", "fan")
+
+	var validationErr *ValidationError
+
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.Violation{{Field: "fanLevel", Value: "high", Supported: []string{"low"}}}, validationErr.Violations)
+
+	router.Verify(t)
+}
+
+func TestWithValidation_UpdateDeviceACState(t *testing.T) {
+	router := mocks.NewRouter()
+	expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+	expectCapabilities(router, "acState,remoteCapabilities,temperatureUnit")
+	router.Expect(http.MethodPost, "v2/pods/{id}/acStates").
+		WithJSONBody(`{"acState":{"targetTemperature":18}}`).
+		ReturnResult(map[string]string{"status": "Success"})
+
+	s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(ValidationConfig{SnapTemperature: true}))
+
+	_, err := s.UpdateDeviceACState(context.Background(), "1234", models.ACStatePatch{}.WithTargetTemperature(17))
+	assert.NoError(t, err)
+
+	// the current high fan level is not supported in fan mode
+	_, err = s.UpdateDeviceACState(context.Background(), "1234", models.ACStatePatch{}.WithMode(models.ModeFan))
+
+	var validationErr *ValidationError
+
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []models.Violation{{Field: "fanLevel", Value: "high", Supported: []string{"low"}}}, validationErr.Violations)
+
+	router.Verify(t)
+}
+
+func TestWithValidation_unknownCapabilities(t *testing.T) {
+	router := mocks.NewRouter()
+	router.Expect(http.MethodGet, "v2/pods/{id}").ReturnResult(map[string]string{"temperatureUnit": "C"})
+	router.Expect(http.MethodPost, "v2/pods/{id}/acStates").
+		WithJSONBody(`{"acState":{"mode":"coool"}}`).
+		ReturnResult(map[string]string{"status": "Success"})
+
+	unknown := []models.Violation{}
+	s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(ValidationConfig{
+		OnUnknownValues: func(deviceID string, violations []models.Violation) {
+			unknown = append(unknown, violations...)
+		},
+	}))
+
+	_, err := s.SetDeviceACState(context.Background(), "1234", models.ACStateData{Mode: "coool"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Violation{{Field: "mode", Value: "coool", Unknown: true}}, unknown)
+	router.Verify(t)
+}
+
+func TestWithValidation_deviceError(t *testing.T) {
+	router := mocks.NewRouter()
+	router.Expect(http.MethodGet, "v2/pods/{id}").ReturnError(http.StatusNotFound, "NotFound", "")
+
+	s := NewClient("api-key", WithHTTPClient(router.Client()), WithValidation(ValidationConfig{}))
+	_, err := s.SetDeviceACState(context.Background(), "1234", models.ACStateData{})
+
+	assert.EqualError(t, err, "failed setting device ac state: \n\tfailed validating ac state \n\t"+
+		"failed getting device \n\tsensibo: GET v2/pods/1234: 404 Not Found: NotFound")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	router.Verify(t)
+}